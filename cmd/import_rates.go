@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/fx"
+	"gobank/internal/util"
+	"log"
+)
+
+// runImportRates loads exchange rates from a file into the exchange_rates table:
+//
+//	go run ./cmd import-rates -file eurofxref-hist.xml -format ecb
+func runImportRates(config util.Config, args []string) {
+	flags := flag.NewFlagSet("import-rates", flag.ExitOnError)
+	path := flags.String("file", "", "path to the rates file")
+	format := flags.String("format", "ecb", "file format: ecb or csv")
+	_ = flags.Parse(args)
+
+	if *path == "" {
+		log.Fatal("-file is required")
+	}
+
+	var provider fx.Provider
+	switch *format {
+	case "ecb":
+		provider = fx.NewECBFileProvider(*path)
+	case "csv":
+		provider = fx.NewCSVFileProvider(*path)
+	default:
+		log.Fatalf("unknown format %q, expected ecb or csv", *format)
+	}
+
+	ctx := context.Background()
+	rates, err := provider.Rates(ctx)
+	if err != nil {
+		log.Fatal("cannot read rates: ", err)
+	}
+
+	var params []db.UpsertExchangeRateParams
+	skipped := 0
+	for _, rate := range rates {
+		if !util.IsSupportedCurrency(rate.Base) || !util.IsSupportedCurrency(rate.Quote) {
+			skipped++
+			continue
+		}
+
+		params = append(params, db.UpsertExchangeRateParams{
+			BaseCurrency:  rate.Base,
+			QuoteCurrency: rate.Quote,
+			Rate:          fx.FormatRate(rate.Value),
+			Source:        provider.Name(),
+			EffectiveAt:   rate.EffectiveAt,
+		})
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+	defer conn.Close()
+
+	imported, err := db.NewSQLStore(conn, config).ImportExchangeRatesTx(ctx, params)
+	if err != nil {
+		log.Fatal("cannot import rates: ", err)
+	}
+
+	fmt.Printf("imported %d rates, skipped %d rates in unsupported currencies\n", len(imported), skipped)
+}
@@ -4,6 +4,7 @@ import (
 	"gobank/internal/api"
 	"gobank/internal/util"
 	"log"
+	"os"
 )
 
 func main() {
@@ -12,5 +13,19 @@ func main() {
 		log.Fatal("cannot load config:", err)
 	}
 
-	api.NewServer(config).Run()
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
+	switch command {
+	case "serve":
+		api.NewServer(config).Run()
+	case "import-rates":
+		runImportRates(config, args)
+	case "verify-ledger":
+		runVerifyLedger(config, args)
+	default:
+		log.Fatalf("unknown command %q, expected one of: serve, import-rates, verify-ledger", command)
+	}
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/ledger"
+	"gobank/internal/util"
+	"log"
+	"os"
+)
+
+// runVerifyLedger checks that the ledger adds up and prints a JSON report to stdout.
+// It exits with status 1 if any violation is found:
+//
+//	go run ./cmd verify-ledger -batch-size 1000
+func runVerifyLedger(config util.Config, args []string) {
+	flags := flag.NewFlagSet("verify-ledger", flag.ExitOnError)
+	batchSize := flags.Int("batch-size", 1000, "number of accounts checked per batch")
+	_ = flags.Parse(args)
+
+	if *batchSize < 1 {
+		log.Fatal("-batch-size must be positive")
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+	defer conn.Close()
+
+	report, err := ledger.Verify(context.Background(), db.New(conn), int32(*batchSize))
+	if err != nil {
+		log.Fatal("cannot verify ledger: ", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("cannot write report: ", err)
+	}
+
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
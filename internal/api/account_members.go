@@ -0,0 +1,290 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"time"
+)
+
+type inviteAccountMemberRequest struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=owner spender viewer"`
+	// SpendLimit is the largest amount a spender can send per UTC day, fees and holds included, no limit by default.
+	// It isn't an allowance over a period, the transfer limits of the account cap the total.
+	SpendLimit int64 `json:"spend_limit" binding:"omitempty,gt=0"`
+}
+
+// handleInviteAccountMember invites a user to share an account, they become a member once they accept.
+// Only owners can invite.
+func (s *Server) handleInviteAccountMember(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req inviteAccountMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.SpendLimit > 0 && req.Role != db.AccountMemberSpender {
+		handleBadRequest(ctx, errors.New("only spenders have a spend limit"))
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	member, ok := s.authorizeAccount(ctx, account, accessManage)
+	if !ok {
+		return
+	}
+
+	if account.Status == db.AccountClosed {
+		handleUnprocessableEntity(ctx, db.ErrAccountClosed)
+		return
+	}
+
+	user, err := s.store.GetUserByUsername(ctx, req.Username)
+	if err == sql.ErrNoRows || user.Role == util.SystemRole {
+		handleNotFound(ctx, errors.New("user not found"))
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	errAlreadyMember := errors.New("user is already a member of the account or invited")
+	if user.ID == account.OwnerID {
+		handleConflict(ctx, errAlreadyMember)
+		return
+	}
+
+	invited, err := s.store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID:  account.ID,
+		UserID:     user.ID,
+		Role:       req.Role,
+		SpendLimit: sql.NullInt64{Int64: req.SpendLimit, Valid: req.SpendLimit > 0},
+		Status:     db.AccountMemberInvited,
+		InvitedBy:  sql.NullInt64{Int64: member.UserID, Valid: true},
+	})
+	if isDBUniqueError(err) {
+		handleConflict(ctx, errAlreadyMember)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, newAccountMemberResponse(invited))
+}
+
+// handleListAccountMembers returns the members of an account and the pending invitations, to any of its members.
+func (s *Server) handleListAccountMembers(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	members, err := s.store.ListAccountMembers(ctx, account.ID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountMembersResponse(members))
+}
+
+type accountMemberRequest struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+	UserID    int64 `uri:"user_id" binding:"required,min=1"`
+}
+
+// handleRemoveAccountMember removes a member or an invitation from an account. Owners can remove anyone
+// but the holder of the account, other members can only leave. The scheduled transfers and holds the member
+// created from the account are cancelled with them.
+func (s *Server) handleRemoveAccountMember(ctx *gin.Context) {
+	var uri accountMemberRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.AccountID)
+	if !ok {
+		return
+	}
+
+	access := accessManage
+	if uri.UserID == getAuthPayload(ctx).UserID {
+		access = accessView
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, access); !ok {
+		return
+	}
+
+	if uri.UserID == account.OwnerID {
+		err := errors.New("the holder of the account can't be removed, the account can be closed instead")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	member, err := s.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		UserID:    uri.UserID,
+	})
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	if err := s.store.RemoveAccountMemberTx(ctx, member); err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountMemberResponse(member))
+}
+
+// handleListAccountInvitations returns the pending invitations of the authenticated user to share accounts.
+func (s *Server) handleListAccountInvitations(ctx *gin.Context) {
+	authPayload := getAuthPayload(ctx)
+	invitations, err := s.store.ListUserInvitations(ctx, authPayload.UserID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountMembersResponse(invitations))
+}
+
+type getInvitationByIdRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// handleAcceptAccountInvitation makes the authenticated user a member of the account they were invited to.
+func (s *Server) handleAcceptAccountInvitation(ctx *gin.Context) {
+	invitation, ok := s.getInvitation(ctx)
+	if !ok {
+		return
+	}
+
+	member, err := s.store.AcceptAccountMember(ctx, invitation.ID)
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountMemberResponse(member))
+}
+
+// handleDeclineAccountInvitation deletes an invitation of the authenticated user.
+func (s *Server) handleDeclineAccountInvitation(ctx *gin.Context) {
+	invitation, ok := s.getInvitation(ctx)
+	if !ok {
+		return
+	}
+
+	if err := s.store.DeleteAccountMember(ctx, invitation.ID); err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountMemberResponse(invitation))
+}
+
+// getInvitation loads a pending invitation of the authenticated user, the invitations of others are not found.
+func (s *Server) getInvitation(ctx *gin.Context) (db.AccountMember, bool) {
+	var uri getInvitationByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return db.AccountMember{}, false
+	}
+
+	invitation, err := s.store.GetAccountMemberByID(ctx, uri.ID)
+	authPayload := getAuthPayload(ctx)
+	if err == nil && (invitation.UserID != authPayload.UserID || invitation.Status != db.AccountMemberInvited) {
+		err = sql.ErrNoRows
+	}
+
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return invitation, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return invitation, false
+	}
+
+	return invitation, true
+}
+
+type accountMemberResponse struct {
+	ID         int64      `json:"id"`
+	AccountID  int64      `json:"account_id"`
+	UserID     int64      `json:"user_id"`
+	Role       string     `json:"role"`
+	SpendLimit int64      `json:"spend_limit,omitempty"`
+	Status     string     `json:"status"`
+	InvitedBy  int64      `json:"invited_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+func newAccountMemberResponse(member db.AccountMember) accountMemberResponse {
+	res := accountMemberResponse{
+		ID:         member.ID,
+		AccountID:  member.AccountID,
+		UserID:     member.UserID,
+		Role:       member.Role,
+		SpendLimit: member.SpendLimit.Int64,
+		Status:     member.Status,
+		InvitedBy:  member.InvitedBy.Int64,
+		CreatedAt:  member.CreatedAt,
+	}
+	if member.AcceptedAt.Valid {
+		res.AcceptedAt = &member.AcceptedAt.Time
+	}
+	return res
+}
+
+func newAccountMembersResponse(members []db.AccountMember) []accountMemberResponse {
+	res := make([]accountMemberResponse, 0, len(members))
+	for _, member := range members {
+		res = append(res, newAccountMemberResponse(member))
+	}
+	return res
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"io"
+)
+
+type closeAccountRequest struct {
+	Reason string `json:"reason" binding:"max=280"`
+}
+
+// handleCloseAccount closes an account, only its owners can and it has to be emptied first.
+func (s *Server) handleCloseAccount(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	// the body is optional, the reason is only kept for the record
+	var req closeAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessManage); !ok {
+		return
+	}
+
+	authPayload := getAuthPayload(ctx)
+	s.changeAccountStatus(ctx, db.ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    db.AccountClosed,
+		Reason:    req.Reason,
+		ChangedBy: authPayload.UserID,
+	})
+}
+
+type freezeAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=280"`
+}
+
+// handleFreezeAccount stops an account from sending money, only admins can do it.
+func (s *Server) handleFreezeAccount(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req freezeAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	s.changeAccountStatusAsAdmin(ctx, uri.ID, db.AccountFrozen, req.Reason)
+}
+
+type unfreezeAccountRequest struct {
+	Reason string `json:"reason" binding:"max=280"`
+}
+
+// handleUnfreezeAccount makes a frozen account active again, only admins can do it.
+func (s *Server) handleUnfreezeAccount(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req unfreezeAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	s.changeAccountStatusAsAdmin(ctx, uri.ID, db.AccountActive, req.Reason)
+}
+
+func (s *Server) changeAccountStatusAsAdmin(ctx *gin.Context, accountID int64, status string, reason string) {
+	if !s.authorizeAdmin(ctx, errors.New("only admins can freeze and unfreeze accounts")) {
+		return
+	}
+
+	account, ok := s.getAccount(ctx, accountID)
+	if !ok {
+		return
+	}
+
+	if account.Kind != db.AccountKindCustomer {
+		err := errors.New("system accounts can't be frozen")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	s.changeAccountStatus(ctx, db.ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    status,
+		Reason:    reason,
+		ChangedBy: getAuthPayload(ctx).UserID,
+	})
+}
+
+func (s *Server) changeAccountStatus(ctx *gin.Context, arg db.ChangeAccountStatusTxParams) {
+	result, err := s.store.ChangeAccountStatusTx(ctx, arg)
+	if errors.Is(err, db.ErrInvalidAccountStatus) ||
+		errors.Is(err, db.ErrAccountNotEmpty) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, result)
+}
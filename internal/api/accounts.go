@@ -5,6 +5,9 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	db "gobank/internal/db/sqlc"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type getAccountByIdRequest struct {
@@ -30,10 +33,7 @@ func (s *Server) handleGetAccountById(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := getAuthPayload(ctx)
-	if account.OwnerID != authPayload.UserID {
-		err := errors.New("account doesn't belong to the authenticated user")
-		handleForbidden(ctx, err)
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
 		return
 	}
 
@@ -42,8 +42,15 @@ func (s *Server) handleGetAccountById(ctx *gin.Context) {
 
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	// Name defaults to the currency, it has to be unique among the open accounts of the user.
+	Name    string `json:"name" binding:"max=40"`
+	Purpose string `json:"purpose" binding:"max=140"`
+	// Primary makes the account the one money sent to the user in its currency goes to,
+	// the first account of a currency is always primary.
+	Primary bool `json:"primary"`
 }
 
+// handleCreateAccount opens an account for the authenticated user, who can have several per currency.
 func (s *Server) handleCreateAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -51,14 +58,22 @@ func (s *Server) handleCreateAccount(ctx *gin.Context) {
 		return
 	}
 
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = req.Currency
+	}
+
 	authPayload := getAuthPayload(ctx)
-	account, err := s.store.CreateAccount(ctx, db.CreateAccountParams{
-		OwnerID:  authPayload.UserID,
-		Currency: req.Currency,
+	account, err := s.store.CreateAccountTx(ctx, db.CreateAccountParams{
+		OwnerID:   authPayload.UserID,
+		Currency:  req.Currency,
+		Name:      name,
+		Purpose:   req.Purpose,
+		IsPrimary: req.Primary,
 	})
 
-	if isDBUniqueError(err) {
-		handleForbidden(ctx, err)
+	if errors.Is(err, db.ErrAccountNameTaken) {
+		handleConflict(ctx, err)
 		return
 	}
 
@@ -69,3 +84,182 @@ func (s *Server) handleCreateAccount(ctx *gin.Context) {
 
 	handleCreated(ctx, account)
 }
+
+type updateAccountRequest struct {
+	Name    *string `json:"name" binding:"omitempty,max=40"`
+	Purpose *string `json:"purpose" binding:"omitempty,max=140"`
+	// Primary can only be set, another account becomes primary by setting it there.
+	Primary bool `json:"primary"`
+}
+
+// handleUpdateAccount renames an account of the authenticated user, changes its purpose or makes it primary.
+func (s *Server) handleUpdateAccount(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req updateAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessManage); !ok {
+		return
+	}
+
+	arg := db.UpdateAccountTxParams{
+		ID:      account.ID,
+		Primary: req.Primary,
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			handleBadRequest(ctx, errors.New("name can't be empty"))
+			return
+		}
+		arg.Name = sql.NullString{String: name, Valid: true}
+	}
+
+	if req.Purpose != nil {
+		arg.Purpose = sql.NullString{String: *req.Purpose, Valid: true}
+	}
+
+	account, err := s.store.UpdateAccountTx(ctx, arg)
+	if errors.Is(err, db.ErrAccountNameTaken) {
+		handleConflict(ctx, err)
+		return
+	}
+
+	if errors.Is(err, db.ErrAccountClosed) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, account)
+}
+
+type listAccountsRequest struct {
+	Currency string `form:"currency" binding:"omitempty,currency"`
+	// Sort is created_at for the newest first, balance for the largest first, or currency.
+	Sort   string `form:"sort" binding:"omitempty,oneof=created_at balance currency"`
+	Cursor string `form:"cursor"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type listAccountsResponse struct {
+	Accounts   []db.Account `json:"accounts"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	// Totals are per currency over every account listed, not only this page.
+	Totals []db.ListOwnerAccountTotalsRow `json:"totals"`
+}
+
+// handleListAccounts returns the accounts the authenticated user holds or is a member of,
+// with their balance totals per currency.
+func (s *Server) handleListAccounts(ctx *gin.Context) {
+	var req listAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.Sort == "" {
+		req.Sort = "created_at"
+	}
+
+	authPayload := getAuthPayload(ctx)
+	currency := sql.NullString{String: req.Currency, Valid: req.Currency != ""}
+
+	limit := pageSize(req.Limit)
+	arg := db.ListOwnerAccountsParams{
+		OwnerID:  authPayload.UserID,
+		Currency: currency,
+		Sort:     req.Sort,
+		PageSize: limit + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeSortCursor(req.Cursor, req.Sort)
+		if err == nil {
+			err = setAccountsCursor(&arg, cursor)
+		}
+		if err != nil {
+			handleBadRequest(ctx, err)
+			return
+		}
+	}
+
+	accounts, err := s.store.ListOwnerAccounts(ctx, arg)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	totals, err := s.store.ListOwnerAccountTotals(ctx, db.ListOwnerAccountTotalsParams{
+		OwnerID:  authPayload.UserID,
+		Currency: currency,
+	})
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := listAccountsResponse{
+		Accounts: accounts,
+		Totals:   totals,
+	}
+
+	if len(accounts) > int(limit) {
+		res.Accounts = accounts[:limit]
+		res.NextCursor = encodeSortCursor(accountsCursor(req.Sort, res.Accounts[limit-1]))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+func accountsCursor(sort string, last db.Account) sortCursor {
+	cursor := sortCursor{Sort: sort, ID: last.ID}
+	switch sort {
+	case "balance":
+		cursor.Key = strconv.FormatInt(last.Balance, 10)
+	case "currency":
+		cursor.Key = last.Currency
+	default:
+		cursor.Key = strconv.FormatInt(last.CreatedAt.UnixMicro(), 10)
+	}
+	return cursor
+}
+
+func setAccountsCursor(arg *db.ListOwnerAccountsParams, cursor sortCursor) error {
+	arg.CursorID = sql.NullInt64{Int64: cursor.ID, Valid: true}
+	switch cursor.Sort {
+	case "balance":
+		balance, err := strconv.ParseInt(cursor.Key, 10, 64)
+		if err != nil {
+			return ErrInvalidCursor
+		}
+		arg.CursorBalance = sql.NullInt64{Int64: balance, Valid: true}
+	case "currency":
+		arg.CursorCurrency = sql.NullString{String: cursor.Key, Valid: true}
+	default:
+		micros, err := strconv.ParseInt(cursor.Key, 10, 64)
+		if err != nil {
+			return ErrInvalidCursor
+		}
+		arg.CursorCreatedAt = sql.NullTime{Time: time.UnixMicro(micros), Valid: true}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+)
+
+// accountAccess is what a member can do with an account, each level includes the ones below it.
+type accountAccess int
+
+const (
+	// accessView reads the account, its transfers, statements and settings.
+	accessView accountAccess = iota
+	// accessSpend moves money in and out of the account, up to the daily spend limit of the member.
+	accessSpend
+	// accessManage changes the account itself and its members.
+	accessManage
+)
+
+var memberAccess = map[string]accountAccess{
+	db.AccountMemberViewer:  accessView,
+	db.AccountMemberSpender: accessSpend,
+	db.AccountMemberOwner:   accessManage,
+}
+
+var errNotAccountMember = errors.New("account doesn't belong to the authenticated user")
+
+// accountMember returns the active membership of the authenticated user in the account, ok is false for anyone else.
+// The holder of the account is always one of its owners.
+func (s *Server) accountMember(ctx *gin.Context, account db.Account) (member db.AccountMember, ok bool, err error) {
+	authPayload := getAuthPayload(ctx)
+	if account.OwnerID == authPayload.UserID {
+		member = db.AccountMember{
+			AccountID: account.ID,
+			UserID:    authPayload.UserID,
+			Role:      db.AccountMemberOwner,
+			Status:    db.AccountMemberActive,
+		}
+		return member, true, nil
+	}
+
+	member, err = s.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		UserID:    authPayload.UserID,
+	})
+	if err == sql.ErrNoRows {
+		return member, false, nil
+	}
+	if err != nil {
+		return member, false, err
+	}
+
+	return member, member.Status == db.AccountMemberActive, nil
+}
+
+// canAccessAccount tells whether the authenticated user has the access to the account, without writing a response.
+func (s *Server) canAccessAccount(ctx *gin.Context, account db.Account, access accountAccess) (bool, error) {
+	member, ok, err := s.accountMember(ctx, account)
+	return ok && memberAccess[member.Role] >= access, err
+}
+
+// authorizeAccount checks that the authenticated user has the access to the account, the response is written when not.
+func (s *Server) authorizeAccount(ctx *gin.Context, account db.Account, access accountAccess) (db.AccountMember, bool) {
+	member, ok, err := s.accountMember(ctx, account)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return member, false
+	}
+
+	if !ok {
+		handleForbidden(ctx, errNotAccountMember)
+		return member, false
+	}
+
+	if memberAccess[member.Role] < access {
+		err := fmt.Errorf("the %s role of the account member doesn't allow this", member.Role)
+		handleForbidden(ctx, err)
+		return member, false
+	}
+
+	return member, true
+}
+
+// createdBy is the member as the creator of the debits they make, for their spend limit.
+func createdBy(member db.AccountMember) sql.NullInt64 {
+	return sql.NullInt64{Int64: member.UserID, Valid: true}
+}
+
+// authorizeAdmin checks that the authenticated user is an admin, the response is written with err when not.
+func (s *Server) authorizeAdmin(ctx *gin.Context, err error) bool {
+	authPayload := getAuthPayload(ctx)
+	user, dbErr := s.store.GetUser(ctx, authPayload.UserID)
+	if dbErr != nil {
+		handleInternalServerError(ctx, dbErr)
+		return false
+	}
+
+	if user.Role != util.AdminRole {
+		handleForbidden(ctx, err)
+		return false
+	}
+
+	return true
+}
+
+// authorizeSpend checks that the authenticated user can make the debits from the account, see checkSpendLimit.
+func (s *Server) authorizeSpend(ctx *gin.Context, account db.Account, debits ...db.TransferTxParams) (db.AccountMember, bool) {
+	member, ok := s.authorizeAccount(ctx, account, accessSpend)
+	if !ok {
+		return member, false
+	}
+
+	return member, s.checkSpendLimit(ctx, member, account, debits...)
+}
+
+// checkSpendLimit rejects early the debits of one request, their amounts and fees, that are over the spend limit
+// of the member on their own. The limit is a daily allowance, the store checks what is left of it for the day
+// when the money moves, from the debits made with the member as their creator.
+func (s *Server) checkSpendLimit(ctx *gin.Context, member db.AccountMember, account db.Account, debits ...db.TransferTxParams) bool {
+	if !member.SpendLimit.Valid {
+		return true
+	}
+
+	var total int64
+	for _, debit := range debits {
+		total += debit.Amount + s.store.PreviewTransferFees(account, debit).Total
+	}
+
+	if total > member.SpendLimit.Int64 {
+		handleUnprocessableEntity(ctx, db.ErrSpendLimitExceeded)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/api/middlewares"
+	"gobank/internal/auth/token"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// memberStore serves a shared account and its members from memory, the other methods of db.Store are not implemented.
+type memberStore struct {
+	db.Store
+	users    map[int64]db.User
+	accounts map[int64]db.Account
+	members  map[db.GetAccountMemberParams]db.AccountMember
+	// transfers are the transfers made, in order
+	transfers []db.TransferTxParams
+}
+
+func (s *memberStore) GetUser(_ context.Context, id int64) (db.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return user, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (s *memberStore) GetAccount(_ context.Context, id int64) (db.Account, error) {
+	account, ok := s.accounts[id]
+	if !ok {
+		return account, sql.ErrNoRows
+	}
+	return account, nil
+}
+
+func (s *memberStore) GetAccountMember(_ context.Context, arg db.GetAccountMemberParams) (db.AccountMember, error) {
+	member, ok := s.members[arg]
+	if !ok {
+		return member, sql.ErrNoRows
+	}
+	return member, nil
+}
+
+func (s *memberStore) PreviewTransferFees(_ db.Account, _ db.TransferTxParams) db.TransferFees {
+	// a flat fee, so the spend limit is seen to include it
+	return db.TransferFees{Total: 10}
+}
+
+func (s *memberStore) TransferTx(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	s.transfers = append(s.transfers, arg)
+	return db.TransferTxResult{
+		Transfer:      db.Transfer{SenderID: arg.SenderID, RecipientID: arg.RecipientID, Amount: arg.Amount},
+		SenderAccount: s.accounts[arg.SenderID],
+	}, nil
+}
+
+func (s *memberStore) UpdateAccountTx(_ context.Context, arg db.UpdateAccountTxParams) (db.Account, error) {
+	account := s.accounts[arg.ID]
+	account.Name = arg.Name.String
+	return account, nil
+}
+
+func (s *memberStore) ChangeAccountStatusTx(_ context.Context, arg db.ChangeAccountStatusTxParams) (db.ChangeAccountStatusTxResult, error) {
+	account := s.accounts[arg.AccountID]
+	account.Status = arg.Status
+	return db.ChangeAccountStatusTxResult{Account: account}, nil
+}
+
+const (
+	holderID int64 = iota + 1
+	viewerID
+	spenderID
+	ownerID
+	invitedID
+	removedID
+	adminID
+	otherID
+)
+
+const (
+	sharedAccountID int64 = iota + 1
+	otherAccountID
+)
+
+func newMemberStore() *memberStore {
+	store := &memberStore{
+		users: map[int64]db.User{
+			adminID: {ID: adminID, Role: util.AdminRole},
+		},
+		accounts: map[int64]db.Account{
+			sharedAccountID: {ID: sharedAccountID, OwnerID: holderID, Currency: util.EUR, Kind: db.AccountKindCustomer, Status: db.AccountActive},
+			otherAccountID:  {ID: otherAccountID, OwnerID: otherID, Currency: util.EUR, Kind: db.AccountKindCustomer, Status: db.AccountActive},
+		},
+		members: make(map[db.GetAccountMemberParams]db.AccountMember),
+	}
+	for _, id := range []int64{holderID, viewerID, spenderID, ownerID, invitedID, removedID, otherID} {
+		store.users[id] = db.User{ID: id, Role: util.CustomerRole}
+	}
+
+	members := []db.AccountMember{
+		{UserID: viewerID, Role: db.AccountMemberViewer, Status: db.AccountMemberActive},
+		{UserID: spenderID, Role: db.AccountMemberSpender, Status: db.AccountMemberActive, SpendLimit: sql.NullInt64{Int64: 100, Valid: true}},
+		{UserID: ownerID, Role: db.AccountMemberOwner, Status: db.AccountMemberActive},
+		{UserID: invitedID, Role: db.AccountMemberOwner, Status: db.AccountMemberInvited},
+	}
+	for _, member := range members {
+		member.AccountID = sharedAccountID
+		store.members[db.GetAccountMemberParams{AccountID: sharedAccountID, UserID: member.UserID}] = member
+	}
+	// removedID was a member, the row is deleted on removal
+
+	return store
+}
+
+func newTestServer(t *testing.T, store db.Store) (*Server, token.Maker) {
+	gin.SetMode(gin.TestMode)
+
+	tokenMaker, err := token.NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	s := &Server{
+		store:      store,
+		tokenMaker: tokenMaker,
+	}
+	s.registerValidators()
+	s.setupRouter()
+
+	return s, tokenMaker
+}
+
+func sendAs(t *testing.T, s *Server, tokenMaker token.Maker, userID int64, method string, url string, body any) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	accessToken, _, err := tokenMaker.CreateToken(userID, fmt.Sprintf("user%d", userID), time.Minute)
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(method, url, bytes.NewReader(payload))
+	request.Header.Set(middlewares.AuthorizationHeaderKey, middlewares.AuthorizationTypeBearer+" "+accessToken)
+	if body != nil {
+		request.Header.Set("Content-Type", gin.MIMEJSON)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAccountMemberAccess(t *testing.T) {
+	s, tokenMaker := newTestServer(t, newMemberStore())
+
+	view := func(userID int64) int {
+		return sendAs(t, s, tokenMaker, userID, http.MethodGet, fmt.Sprintf("/api/accounts/%d", sharedAccountID), nil).Code
+	}
+	spend := func(userID int64, amount int64) int {
+		return sendAs(t, s, tokenMaker, userID, http.MethodPost, "/api/transfers", gin.H{
+			"sender_id":    sharedAccountID,
+			"recipient_id": otherAccountID,
+			"amount":       amount,
+			"currency":     util.EUR,
+		}).Code
+	}
+	manage := func(userID int64) int {
+		return sendAs(t, s, tokenMaker, userID, http.MethodPatch, fmt.Sprintf("/api/accounts/%d", sharedAccountID), gin.H{
+			"name": "Household",
+		}).Code
+	}
+
+	testCases := []struct {
+		name   string
+		userID int64
+		view   int
+		spend  int
+		manage int
+	}{
+		{"holder", holderID, http.StatusOK, http.StatusCreated, http.StatusOK},
+		{"owner", ownerID, http.StatusOK, http.StatusCreated, http.StatusOK},
+		{"spender", spenderID, http.StatusOK, http.StatusCreated, http.StatusForbidden},
+		{"viewer", viewerID, http.StatusOK, http.StatusForbidden, http.StatusForbidden},
+		{"invited", invitedID, http.StatusForbidden, http.StatusForbidden, http.StatusForbidden},
+		{"removed", removedID, http.StatusForbidden, http.StatusForbidden, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.view, view(tc.userID))
+			require.Equal(t, tc.spend, spend(tc.userID, 50))
+			require.Equal(t, tc.manage, manage(tc.userID))
+		})
+	}
+}
+
+func TestAccountMemberSpendLimit(t *testing.T) {
+	store := newMemberStore()
+	s, tokenMaker := newTestServer(t, store)
+
+	send := func(userID int64, amount int64) *httptest.ResponseRecorder {
+		return sendAs(t, s, tokenMaker, userID, http.MethodPost, "/api/transfers", gin.H{
+			"sender_id":    sharedAccountID,
+			"recipient_id": otherAccountID,
+			"amount":       amount,
+			"currency":     util.EUR,
+		})
+	}
+
+	// the limit of 100 includes the fee of 10
+	require.Equal(t, http.StatusCreated, send(spenderID, 90).Code)
+	// the store checks the daily allowance of the member who makes the transfer
+	require.Equal(t, sql.NullInt64{Int64: spenderID, Valid: true}, store.transfers[0].CreatedBy)
+
+	recorder := send(spenderID, 91)
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrSpendLimitExceeded.Error())
+
+	// owners have no spend limit
+	require.Equal(t, http.StatusCreated, send(ownerID, 1000).Code)
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	s, tokenMaker := newTestServer(t, newMemberStore())
+
+	freeze := func(userID int64) int {
+		return sendAs(t, s, tokenMaker, userID, http.MethodPost, fmt.Sprintf("/api/accounts/%d/freeze", sharedAccountID), gin.H{
+			"reason": "test",
+		}).Code
+	}
+
+	require.Equal(t, http.StatusForbidden, freeze(holderID))
+	require.Equal(t, http.StatusForbidden, freeze(ownerID))
+	require.Equal(t, http.StatusOK, freeze(adminID))
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type batchLineRequest struct {
+	RecipientID int64 `json:"recipient_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gt=0"`
+}
+
+type createTransferBatchRequest struct {
+	SenderID int64              `json:"sender_id" form:"sender_id" binding:"required,min=1"`
+	Currency string             `json:"currency" form:"currency" binding:"required,currency"`
+	Lines    []batchLineRequest `json:"lines" form:"-" binding:"omitempty,dive"`
+}
+
+type batchLineResponse struct {
+	Line        int64  `json:"line"`
+	RecipientID int64  `json:"recipient_id"`
+	Amount      int64  `json:"amount"`
+	TransferID  int64  `json:"transfer_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type transferBatchResponse struct {
+	ID          int64               `json:"id"`
+	SenderID    int64               `json:"sender_id"`
+	Currency    string              `json:"currency"`
+	Status      string              `json:"status"`
+	LineCount   int64               `json:"line_count"`
+	TotalAmount int64               `json:"total_amount"`
+	TotalFee    int64               `json:"total_fee"`
+	Error       string              `json:"error,omitempty"`
+	Lines       []batchLineResponse `json:"lines"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+func newTransferBatchResponse(batch db.TransferBatch, lines []db.TransferBatchLine) transferBatchResponse {
+	res := transferBatchResponse{
+		ID:          batch.ID,
+		SenderID:    batch.SenderID,
+		Currency:    batch.Currency,
+		Status:      batch.Status,
+		LineCount:   batch.LineCount,
+		TotalAmount: batch.TotalAmount,
+		TotalFee:    batch.TotalFee,
+		Error:       batch.Error.String,
+		Lines:       make([]batchLineResponse, 0, len(lines)),
+		CreatedAt:   batch.CreatedAt,
+	}
+	for _, line := range lines {
+		res.Lines = append(res.Lines, batchLineResponse{
+			Line:        line.Line,
+			RecipientID: line.RecipientID,
+			Amount:      line.Amount,
+			TransferID:  line.TransferID.Int64,
+			Error:       line.Error.String,
+		})
+	}
+	return res
+}
+
+// handleCreateTransferBatch executes all the lines of a batch from one sender account, or none of them.
+// The lines come as JSON, or for large batches as a CSV file of recipient_id,amount rows uploaded
+// in the file field of a multipart form, with sender_id and currency as form fields.
+// A rejected batch is answered with 422 and the errors of its lines.
+func (s *Server) handleCreateTransferBatch(ctx *gin.Context) {
+	var req createTransferBatchRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		lines, err := s.readBatchCSV(ctx)
+		if err != nil {
+			handleBadRequest(ctx, err)
+			return
+		}
+		req.Lines = lines
+	}
+
+	if len(req.Lines) == 0 || len(req.Lines) > s.config.BatchMaxLines {
+		err := fmt.Errorf("a batch must have between 1 and %d lines", s.config.BatchMaxLines)
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	sender, ok := s.validAccount(ctx, req.SenderID, req.Currency)
+	if !ok {
+		return
+	}
+
+	arg := db.BatchTransferTxParams{
+		SenderID: sender.ID,
+		Currency: sender.Currency,
+		Lines:    make([]db.BatchLine, 0, len(req.Lines)),
+	}
+	debits := make([]db.TransferTxParams, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		arg.Lines = append(arg.Lines, db.BatchLine{RecipientID: line.RecipientID, Amount: line.Amount})
+		debits = append(debits, db.TransferTxParams{Amount: line.Amount, Kind: db.TransferKindBatch})
+	}
+
+	// a batch is sent at once, its total with the fees counts against the spend limit
+	member, ok := s.authorizeSpend(ctx, sender, debits...)
+	if !ok {
+		return
+	}
+	arg.CreatedBy = createdBy(member)
+
+	result, err := s.store.BatchTransferTx(ctx, arg)
+	if errors.Is(err, db.ErrBatchRejected) {
+		ctx.JSON(http.StatusUnprocessableEntity, newTransferBatchResponse(result.Batch, result.Lines))
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, newTransferBatchResponse(result.Batch, result.Lines))
+}
+
+// readBatchCSV reads the lines of the uploaded file, an optional header row is skipped.
+func (s *Server) readBatchCSV(ctx *gin.Context) ([]batchLineRequest, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+
+	var lines []batchLineRequest
+	for row := 1; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if row == 1 && strings.EqualFold(record[0], "recipient_id") {
+			continue
+		}
+
+		if len(lines) == s.config.BatchMaxLines {
+			return nil, fmt.Errorf("a batch must have between 1 and %d lines", s.config.BatchMaxLines)
+		}
+
+		recipientID, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil || recipientID < 1 {
+			return nil, fmt.Errorf("row %d: invalid recipient_id %q", row, record[0])
+		}
+
+		amount, err := strconv.ParseInt(record[1], 10, 64)
+		if err != nil || amount < 1 {
+			return nil, fmt.Errorf("row %d: invalid amount %q", row, record[1])
+		}
+
+		lines = append(lines, batchLineRequest{RecipientID: recipientID, Amount: amount})
+	}
+}
+
+type getTransferBatchRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (s *Server) handleGetTransferBatch(ctx *gin.Context) {
+	var req getTransferBatchRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	batch, err := s.store.GetTransferBatch(ctx, req.ID)
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	sender, ok := s.getAccount(ctx, batch.SenderID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, sender, accessView); !ok {
+		return
+	}
+
+	lines, err := s.store.ListTransferBatchLines(ctx, batch.ID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newTransferBatchResponse(batch, lines))
+}
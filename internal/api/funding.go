@@ -0,0 +1,174 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/fees"
+	"gobank/internal/funding"
+	"log"
+)
+
+type fundingRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"required,currency"`
+	// Instrument is the funding provider's token for the card or bank account the money comes from or goes to.
+	Instrument string `json:"instrument" binding:"required"`
+}
+
+// fundingResponse shows the customer side of a deposit or withdrawal, not the settlement account.
+type fundingResponse struct {
+	Transfer    transferResponse  `json:"transfer"`
+	Account     db.Account        `json:"account"`
+	Entry       entryResponse     `json:"entry"`
+	Fees        []fees.Fee        `json:"fees,omitempty"`
+	FeeTransfer *transferResponse `json:"fee_transfer,omitempty"`
+}
+
+// handleDeposit collects money from the instrument, then credits the account with it.
+func (s *Server) handleDeposit(ctx *gin.Context) {
+	account, req, ok := s.bindFundingRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessSpend); !ok {
+		return
+	}
+
+	// the status is checked before the money is collected, DepositTx checks it again
+	switch {
+	case account.Status == db.AccountClosed:
+		handleUnprocessableEntity(ctx, db.ErrAccountClosed)
+		return
+	case account.Status == db.AccountFrozen && !s.config.FrozenAccountsCanReceive:
+		handleUnprocessableEntity(ctx, db.ErrAccountFrozen)
+		return
+	}
+
+	ref := uuid.NewString()
+	err := s.funding.Collect(ctx, funding.Request{
+		Reference:  ref,
+		AccountID:  account.ID,
+		Amount:     req.Amount,
+		Currency:   account.Currency,
+		Instrument: req.Instrument,
+	})
+	if errors.Is(err, funding.ErrDeclined) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	result, err := s.store.DepositTx(ctx, db.FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      req.Amount,
+		ExternalRef: ref,
+	})
+	if err != nil {
+		// the money was collected, the operation has to be reconciled by its reference
+		log.Printf("deposit %s of %d to account %d was collected but not credited: %v", ref, req.Amount, account.ID, err)
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, fundingResponse{
+		Transfer: newTransferResponse(result.Transfer),
+		Account:  result.RecipientAccount,
+		Entry:    newEntryResponse(result.RecipientEntry),
+	})
+}
+
+// handleWithdrawal debits the account, then pays the money out to the instrument.
+// The debit is reversed if the funding provider declines the payout.
+func (s *Server) handleWithdrawal(ctx *gin.Context) {
+	account, req, ok := s.bindFundingRequest(ctx)
+	if !ok {
+		return
+	}
+
+	member, ok := s.authorizeSpend(ctx, account, db.TransferTxParams{Amount: req.Amount, Kind: db.TransferKindWithdrawal})
+	if !ok {
+		return
+	}
+
+	ref := uuid.NewString()
+	result, err := s.store.WithdrawalTx(ctx, db.FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      req.Amount,
+		ExternalRef: ref,
+		CreatedBy:   createdBy(member),
+	})
+	if errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrLimitExceeded) ||
+		errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrAccountClosed) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	err = s.funding.Payout(ctx, funding.Request{
+		Reference:  ref,
+		AccountID:  account.ID,
+		Amount:     req.Amount,
+		Currency:   account.Currency,
+		Instrument: req.Instrument,
+	})
+	if errors.Is(err, funding.ErrDeclined) {
+		if _, rvErr := s.store.ReverseWithdrawalTx(ctx, result.Transfer.ID); rvErr != nil {
+			handleInternalServerError(ctx, rvErr)
+			return
+		}
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		// the payout may or may not have happened, the withdrawal has to be reconciled by its reference
+		log.Printf("withdrawal %s of %d from account %d has an unknown payout outcome: %v", ref, req.Amount, account.ID, err)
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := fundingResponse{
+		Transfer: newTransferResponse(result.Transfer),
+		Account:  result.SenderAccount,
+		Entry:    newEntryResponse(result.SenderEntry),
+		Fees:     result.Fees,
+	}
+	if result.FeeTransfer != nil {
+		feeTransfer := newTransferResponse(*result.FeeTransfer)
+		res.FeeTransfer = &feeTransfer
+	}
+
+	handleCreated(ctx, res)
+}
+
+func (s *Server) bindFundingRequest(ctx *gin.Context) (db.Account, fundingRequest, bool) {
+	var uri getAccountByIdRequest
+	var req fundingRequest
+	var account db.Account
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return account, req, false
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return account, req, false
+	}
+
+	account, ok := s.validAccount(ctx, uri.ID, req.Currency)
+	return account, req, ok
+}
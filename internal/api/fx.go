@@ -0,0 +1,232 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/fx"
+	"gobank/internal/util"
+	"time"
+)
+
+type createFxQuoteRequest struct {
+	FromCurrency string `json:"from_currency" binding:"required,currency"`
+	ToCurrency   string `json:"to_currency" binding:"required,currency,nefield=FromCurrency"`
+	Amount       int64  `json:"amount" binding:"omitempty,gt=0"`
+}
+
+type fxQuoteResponse struct {
+	ID           uuid.UUID `json:"id"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         string    `json:"rate"`
+	Amount       int64     `json:"amount,omitempty"`
+	ToAmount     int64     `json:"to_amount,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// handleCreateFxQuote locks the current rate between two currencies for config.FxQuoteDuration.
+// The quote id is then passed to a transfer between accounts in those currencies.
+func (s *Server) handleCreateFxQuote(ctx *gin.Context) {
+	var req createFxQuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	table, err := s.loadRateTable(ctx, time.Now())
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	rate, err := table.Rate(req.FromCurrency, req.ToCurrency)
+	if errors.Is(err, fx.ErrRateNotFound) {
+		handleNotFound(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	// an amount that can't be converted gets no quote
+	var toAmount int64
+	if req.Amount > 0 {
+		toAmount, err = fx.Convert(req.Amount, rate)
+		if err != nil {
+			handleBadRequest(ctx, err)
+			return
+		}
+	}
+
+	authPayload := getAuthPayload(ctx)
+	quote, err := s.store.CreateFxQuote(ctx, db.CreateFxQuoteParams{
+		ID:           uuid.New(),
+		UserID:       authPayload.UserID,
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		Rate:         fx.FormatRate(rate),
+		ExpiresAt:    time.Now().Add(s.config.FxQuoteDuration),
+	})
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := fxQuoteResponse{
+		ID:           quote.ID,
+		FromCurrency: quote.FromCurrency,
+		ToCurrency:   quote.ToCurrency,
+		Rate:         quote.Rate,
+		ExpiresAt:    quote.ExpiresAt,
+		CreatedAt:    quote.CreatedAt,
+	}
+	if req.Amount > 0 {
+		res.Amount = req.Amount
+		res.ToAmount = toAmount
+	}
+
+	handleCreated(ctx, res)
+}
+
+type getFxRatesRequest struct {
+	Base string    `form:"base" binding:"required,currency"`
+	At   time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type fxRateResponse struct {
+	Currency string `json:"currency"`
+	Rate     string `json:"rate"`
+}
+
+type getFxRatesResponse struct {
+	Base  string           `json:"base"`
+	At    time.Time        `json:"at"`
+	Rates []fxRateResponse `json:"rates"`
+}
+
+// handleGetFxRates returns the rates of every supported currency against the base currency
+// as they were effective at the given time, now by default.
+func (s *Server) handleGetFxRates(ctx *gin.Context) {
+	var req getFxRatesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.At.IsZero() {
+		req.At = time.Now()
+	}
+
+	table, err := s.loadRateTable(ctx, req.At)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := getFxRatesResponse{
+		Base:  req.Base,
+		At:    req.At,
+		Rates: []fxRateResponse{},
+	}
+
+	for _, currency := range util.SupportedCurrencies() {
+		if currency == req.Base {
+			continue
+		}
+
+		rate, err := table.Rate(req.Base, currency)
+		if errors.Is(err, fx.ErrRateNotFound) {
+			continue
+		}
+
+		if err != nil {
+			handleInternalServerError(ctx, err)
+			return
+		}
+
+		res.Rates = append(res.Rates, fxRateResponse{
+			Currency: currency,
+			Rate:     fx.FormatRate(rate),
+		})
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// loadRateTable builds a rate table from the rates effective at the given time.
+func (s *Server) loadRateTable(ctx *gin.Context, at time.Time) (*fx.Table, error) {
+	rates, err := s.store.ListEffectiveExchangeRates(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	table := fx.NewTable()
+	for _, r := range rates {
+		rate, err := fx.ParseRate(r.Rate)
+		if err != nil {
+			return nil, err
+		}
+		table.Add(r.BaseCurrency, r.QuoteCurrency, rate)
+	}
+
+	return table, nil
+}
+
+// quotedRecipientAmount checks that the quote can be used by the authenticated user for a transfer
+// between the given accounts, and converts the amount with the quoted rate.
+func (s *Server) quotedRecipientAmount(ctx *gin.Context, quoteID uuid.UUID, sender db.Account, recipient db.Account, amount int64) (db.FxQuote, int64, bool) {
+	quote, err := s.store.GetFxQuote(ctx, quoteID)
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return quote, 0, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return quote, 0, false
+	}
+
+	authPayload := getAuthPayload(ctx)
+	if quote.UserID != authPayload.UserID {
+		err := errors.New("fx quote doesn't belong to the authenticated user")
+		handleForbidden(ctx, err)
+		return quote, 0, false
+	}
+
+	if quote.FromCurrency != sender.Currency || quote.ToCurrency != recipient.Currency {
+		err := errors.New("fx quote currencies don't match the accounts")
+		handleBadRequest(ctx, err)
+		return quote, 0, false
+	}
+
+	if quote.UsedAt.Valid || time.Now().After(quote.ExpiresAt) {
+		handleUnprocessableEntity(ctx, db.ErrQuoteUnavailable)
+		return quote, 0, false
+	}
+
+	rate, err := fx.ParseRate(quote.Rate)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return quote, 0, false
+	}
+
+	recipientAmount, err := fx.Convert(amount, rate)
+	if err != nil {
+		handleBadRequest(ctx, err)
+		return quote, 0, false
+	}
+
+	if recipientAmount <= 0 {
+		err := errors.New("amount is too small to be converted")
+		handleBadRequest(ctx, err)
+		return quote, 0, false
+	}
+
+	return quote, recipientAmount, true
+}
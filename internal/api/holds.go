@@ -0,0 +1,267 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"io"
+	"time"
+)
+
+type authorizeHoldRequest struct {
+	AccountID   int64  `json:"account_id" binding:"required,min=1"`
+	RecipientID int64  `json:"recipient_id" binding:"required,min=1,nefield=AccountID"`
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Currency    string `json:"currency" binding:"required,currency"`
+}
+
+// handleAuthorizeHold reserves funds of the account for the recipient, who can capture them until the hold expires.
+func (s *Server) handleAuthorizeHold(ctx *gin.Context) {
+	var req authorizeHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.validAccount(ctx, req.AccountID, req.Currency)
+	if !ok {
+		return
+	}
+
+	member, ok := s.authorizeAccount(ctx, account, accessSpend)
+	if !ok {
+		return
+	}
+
+	recipient, ok := s.validAccount(ctx, req.RecipientID, req.Currency)
+	if !ok {
+		return
+	}
+
+	// the capture is a transfer, its fees count against the spend limit
+	debit := db.TransferTxParams{Amount: req.Amount, Kind: db.TransferKindBetween(account, recipient)}
+	if !s.checkSpendLimit(ctx, member, account, debit) {
+		return
+	}
+
+	result, err := s.store.AuthorizeHoldTx(ctx, db.CreateHoldParams{
+		AccountID:   req.AccountID,
+		RecipientID: req.RecipientID,
+		Amount:      req.Amount,
+		ExpiresAt:   time.Now().Add(s.config.HoldDuration),
+		CreatedBy:   createdBy(member),
+	})
+	if errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrLimitExceeded) ||
+		errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrAccountClosed) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, newHoldResponse(result.Hold))
+}
+
+type getHoldByIdRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// handleGetHold returns a hold to the owner of either of its accounts.
+func (s *Server) handleGetHold(ctx *gin.Context) {
+	var req getHoldByIdRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	hold, ok := s.getHold(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	account, ok := s.getAccount(ctx, hold.AccountID)
+	if !ok {
+		return
+	}
+
+	recipient, ok := s.getAccount(ctx, hold.RecipientID)
+	if !ok {
+		return
+	}
+
+	ok, err := s.canAccessAccount(ctx, account, accessView)
+	if err == nil && !ok {
+		ok, err = s.canAccessAccount(ctx, recipient, accessView)
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	if !ok {
+		err := errors.New("hold doesn't belong to the authenticated user")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newHoldResponse(hold))
+}
+
+type captureHoldRequest struct {
+	// Amount defaults to the held amount.
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// handleCaptureHold transfers the held funds, or part of them, to the recipient. The rest is released.
+func (s *Server) handleCaptureHold(ctx *gin.Context) {
+	var uri getHoldByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	// the body is optional, an empty one captures the whole hold
+	var req captureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	hold, ok := s.getRecipientHold(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	result, err := s.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: req.Amount,
+	})
+	if errors.Is(err, db.ErrHoldNotActive) ||
+		errors.Is(err, db.ErrCaptureExceedsHold) ||
+		errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrLimitExceeded) ||
+		errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrAccountClosed) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, holdCaptureResponse{
+		Hold:     newHoldResponse(result.Hold),
+		Transfer: newTransferResponse(result.Transfer.Transfer),
+	})
+}
+
+// handleVoidHold cancels a hold and releases its funds.
+func (s *Server) handleVoidHold(ctx *gin.Context) {
+	var uri getHoldByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	hold, ok := s.getRecipientHold(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	result, err := s.store.VoidHoldTx(ctx, hold.ID)
+	if errors.Is(err, db.ErrHoldNotActive) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newHoldResponse(result.Hold))
+}
+
+func (s *Server) getHold(ctx *gin.Context, holdID int64) (db.Hold, bool) {
+	hold, err := s.store.GetHold(ctx, holdID)
+
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return hold, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return hold, false
+	}
+
+	return hold, true
+}
+
+// getRecipientHold loads a hold that can be captured or voided by the authenticated user,
+// only the owner of the recipient account can settle a hold.
+func (s *Server) getRecipientHold(ctx *gin.Context, holdID int64) (db.Hold, bool) {
+	hold, ok := s.getHold(ctx, holdID)
+	if !ok {
+		return hold, false
+	}
+
+	recipient, ok := s.getAccount(ctx, hold.RecipientID)
+	if !ok {
+		return hold, false
+	}
+
+	ok, err := s.canAccessAccount(ctx, recipient, accessSpend)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return hold, false
+	}
+
+	if !ok {
+		err := errors.New("only the recipient of the hold can capture or void it")
+		handleForbidden(ctx, err)
+		return hold, false
+	}
+
+	return hold, true
+}
+
+type holdResponse struct {
+	ID             int64     `json:"id"`
+	AccountID      int64     `json:"account_id"`
+	RecipientID    int64     `json:"recipient_id"`
+	Amount         int64     `json:"amount"`
+	Status         string    `json:"status"`
+	CapturedAmount int64     `json:"captured_amount"`
+	TransferID     int64     `json:"transfer_id,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newHoldResponse(hold db.Hold) holdResponse {
+	return holdResponse{
+		ID:             hold.ID,
+		AccountID:      hold.AccountID,
+		RecipientID:    hold.RecipientID,
+		Amount:         hold.Amount,
+		Status:         hold.Status,
+		CapturedAmount: hold.CapturedAmount,
+		TransferID:     hold.TransferID.Int64,
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+	}
+}
+
+type holdCaptureResponse struct {
+	Hold     holdResponse     `json:"hold"`
+	Transfer transferResponse `json:"transfer"`
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/interest"
+	"time"
+)
+
+type accountInterestResponse struct {
+	AccountID int64 `json:"account_id"`
+	RateBps   int64 `json:"rate_bps"`
+	// AccruedTo is the day interest will be accrued for next, null without an interest rate.
+	AccruedTo *time.Time         `json:"accrued_to"`
+	Accrued   db.AccruedInterest `json:"accrued"`
+	// NextCapitalizationAt is when the interest accrued this month and before is paid.
+	NextCapitalizationAt time.Time `json:"next_capitalization_at"`
+}
+
+// handleGetAccountInterest returns the interest rate of an account and the interest it accrued but wasn't paid yet.
+func (s *Server) handleGetAccountInterest(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	accrued, err := s.store.GetAccruedInterest(ctx, account.ID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountInterestResponse(account, accrued))
+}
+
+type updateAccountInterestRequest struct {
+	// RateBps is the yearly rate in basis points, 0 stops the account from earning interest.
+	RateBps int64 `json:"rate_bps" binding:"min=0,max=10000"`
+}
+
+// handleUpdateAccountInterest sets the interest rate of an account, only admins can do it.
+// The new rate applies from the next day that is accrued.
+func (s *Server) handleUpdateAccountInterest(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req updateAccountInterestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if !s.authorizeAdmin(ctx, errors.New("only admins can change the interest rate")) {
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if account.Kind != db.AccountKindCustomer {
+		err := errors.New("system accounts don't earn interest")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	account, err := s.store.SetAccountInterestRate(ctx, db.SetAccountInterestRateParams{
+		ID:              account.ID,
+		InterestRateBps: req.RateBps,
+		Today:           sql.NullTime{Time: interest.Day(time.Now()), Valid: true},
+	})
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	accrued, err := s.store.GetAccruedInterest(ctx, account.ID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newAccountInterestResponse(account, accrued))
+}
+
+func newAccountInterestResponse(account db.Account, accrued db.AccruedInterest) accountInterestResponse {
+	res := accountInterestResponse{
+		AccountID:            account.ID,
+		RateBps:              account.InterestRateBps,
+		Accrued:              accrued,
+		NextCapitalizationAt: interest.MonthStart(time.Now()).AddDate(0, 1, 0),
+	}
+	if account.InterestAccruedTo.Valid {
+		res.AccruedTo = &account.InterestAccruedTo.Time
+	}
+	return res
+}
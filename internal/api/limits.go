@@ -0,0 +1,81 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// limitResponse shows how much of a limit is used, Limit and Remaining are null when there is no limit.
+type limitResponse struct {
+	Limit     *int64 `json:"limit"`
+	Used      int64  `json:"used"`
+	Remaining *int64 `json:"remaining"`
+}
+
+func newLimitResponse(limit int64, used int64) limitResponse {
+	res := limitResponse{
+		Used: used,
+	}
+	if limit > 0 {
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		res.Limit = &limit
+		res.Remaining = &remaining
+	}
+	return res
+}
+
+type accountLimitsResponse struct {
+	AccountID     int64         `json:"account_id"`
+	Currency      string        `json:"currency"`
+	PerTransfer   *int64        `json:"per_transfer"`
+	DailyAmount   limitResponse `json:"daily_amount"`
+	DailyCount    limitResponse `json:"daily_count"`
+	MonthlyAmount limitResponse `json:"monthly_amount"`
+	MonthlyCount  limitResponse `json:"monthly_count"`
+	// DailyResetAt and MonthlyResetAt are the UTC midnights when the usage goes back to zero.
+	DailyResetAt   time.Time `json:"daily_reset_at"`
+	MonthlyResetAt time.Time `json:"monthly_reset_at"`
+}
+
+// handleGetAccountLimits returns the transfer limits of an account and the headroom left under each of them.
+func (s *Server) handleGetAccountLimits(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	result, err := s.store.AccountLimitsTx(ctx, account)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := accountLimitsResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		DailyAmount:    newLimitResponse(result.Limits.DailyAmount, result.Usage.DailyAmount),
+		DailyCount:     newLimitResponse(result.Limits.DailyCount, result.Usage.DailyCount),
+		MonthlyAmount:  newLimitResponse(result.Limits.MonthlyAmount, result.Usage.MonthlyAmount),
+		MonthlyCount:   newLimitResponse(result.Limits.MonthlyCount, result.Usage.MonthlyCount),
+		DailyResetAt:   result.DayStart.AddDate(0, 0, 1),
+		MonthlyResetAt: result.MonthStart.AddDate(0, 1, 0),
+	}
+	if result.Limits.PerTransfer > 0 {
+		res.PerTransfer = &result.Limits.PerTransfer
+	}
+
+	handleSuccess(ctx, res)
+}
@@ -51,7 +51,11 @@ func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 }
 
 func handleAbortWithUnauthorized(ctx *gin.Context, err error) {
-	ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+	handleAbortWithError(ctx, err, http.StatusUnauthorized)
+}
+
+func handleAbortWithError(ctx *gin.Context, err error, code int) {
+	ctx.AbortWithStatusJSON(code, gin.H{
 		"error": err.Error(),
 	})
 }
@@ -0,0 +1,187 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gobank/internal/auth/token"
+	db "gobank/internal/db/sqlc"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeaderKey = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
+var ErrIdempotencyKeyTooLong = fmt.Errorf("idempotency key must be at most %d characters", maxIdempotencyKeyLength)
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+var ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+var ErrIdempotencyKeyUnauthenticated = errors.New("idempotency keys are only supported on authenticated requests")
+
+// IdempotencyMiddleware makes a handler safe to retry when the client sends an Idempotency-Key header.
+// The first request with a key is executed and its response is stored for ttl, retries with the same
+// key and body get the stored response back without running the handler again.
+// Must be registered after AuthMiddleware, keys are scoped per user. The stored responses are kept in
+// plaintext, so it must not be used on routes returning credentials such as the auth ones.
+func IdempotencyMiddleware(store db.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeaderKey)
+		if len(key) == 0 {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			handleAbortWithError(ctx, ErrIdempotencyKeyTooLong, http.StatusBadRequest)
+			return
+		}
+
+		payload, ok := ctx.Get(AuthorizationPayloadKey)
+		if !ok {
+			handleAbortWithUnauthorized(ctx, ErrIdempotencyKeyUnauthenticated)
+			return
+		}
+		userID := payload.(*token.Payload).UserID
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			handleAbortWithError(ctx, err, http.StatusBadRequest)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash := hashRequest(ctx.Request.Method, ctx.FullPath(), body)
+
+		record, created, err := acquireIdempotencyKey(ctx, store, db.CreateIdempotencyKeyParams{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().Add(ttl),
+		})
+		if err != nil {
+			handleAbortWithError(ctx, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !created {
+			replayIdempotentResponse(ctx, record, requestHash)
+			return
+		}
+
+		// the key is released when the handler panics or fails with a server error, so the client can retry
+		// with the same key. Once the handler succeeded the key is kept even if its response can't be stored,
+		// retries then get ErrIdempotencyKeyInProgress until it expires instead of running the handler again.
+		completed := false
+		defer func() {
+			if !completed {
+				_ = store.DeleteIdempotencyKey(context.Background(), db.DeleteIdempotencyKeyParams{
+					UserID: userID,
+					Key:    key,
+				})
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		completed = true
+
+		err = store.SaveIdempotencyKeyResponse(ctx, db.SaveIdempotencyKeyResponseParams{
+			UserID:       userID,
+			Key:          key,
+			StatusCode:   int32(recorder.Status()),
+			ResponseBody: recorder.body.Bytes(),
+		})
+		if err != nil {
+			log.Printf("cannot save the response of idempotency key %q of user %d: %v", key, userID, err)
+		}
+	}
+}
+
+// acquireIdempotencyKey creates the key record, or returns the existing one if the key was already used.
+// An expired record is removed and the key is acquired again.
+func acquireIdempotencyKey(ctx *gin.Context, store db.Store, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, bool, error) {
+	for {
+		record, err := store.CreateIdempotencyKey(ctx, arg)
+		if err == nil {
+			return record, true, nil
+		}
+		if err != sql.ErrNoRows {
+			return record, false, err
+		}
+
+		record, err = store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+			UserID: arg.UserID,
+			Key:    arg.Key,
+		})
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return record, false, err
+		}
+
+		if time.Now().Before(record.ExpiresAt) {
+			return record, false, nil
+		}
+
+		_, err = store.DeleteExpiredIdempotencyKey(ctx, db.DeleteExpiredIdempotencyKeyParams{
+			UserID: arg.UserID,
+			Key:    arg.Key,
+		})
+		if err != nil {
+			return record, false, err
+		}
+	}
+}
+
+func replayIdempotentResponse(ctx *gin.Context, record db.IdempotencyKey, requestHash string) {
+	if record.RequestHash != requestHash {
+		handleAbortWithError(ctx, ErrIdempotencyKeyReused, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if record.StatusCode == 0 {
+		handleAbortWithError(ctx, ErrIdempotencyKeyInProgress, http.StatusConflict)
+		return
+	}
+
+	ctx.Header("Idempotent-Replayed", "true")
+	ctx.Data(int(record.StatusCode), gin.MIMEJSON+"; charset=utf-8", record.ResponseBody)
+	ctx.Abort()
+}
+
+func hashRequest(method string, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
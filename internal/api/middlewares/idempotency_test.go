@@ -0,0 +1,218 @@
+package middlewares
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/auth/token"
+	db "gobank/internal/db/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// idempotencyStore keeps the idempotency keys in memory, the other methods of db.Store are not implemented.
+type idempotencyStore struct {
+	db.Store
+	keys map[db.GetIdempotencyKeyParams]db.IdempotencyKey
+	// saveErr is returned when a response is saved
+	saveErr error
+}
+
+func newIdempotencyStore() *idempotencyStore {
+	return &idempotencyStore{keys: make(map[db.GetIdempotencyKeyParams]db.IdempotencyKey)}
+}
+
+func (s *idempotencyStore) CreateIdempotencyKey(_ context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	id := db.GetIdempotencyKeyParams{UserID: arg.UserID, Key: arg.Key}
+	if _, ok := s.keys[id]; ok {
+		return db.IdempotencyKey{}, sql.ErrNoRows
+	}
+
+	s.keys[id] = db.IdempotencyKey{
+		UserID:      arg.UserID,
+		Key:         arg.Key,
+		RequestHash: arg.RequestHash,
+		ExpiresAt:   arg.ExpiresAt,
+		CreatedAt:   time.Now(),
+	}
+	return s.keys[id], nil
+}
+
+func (s *idempotencyStore) GetIdempotencyKey(_ context.Context, arg db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	record, ok := s.keys[arg]
+	if !ok {
+		return record, sql.ErrNoRows
+	}
+	return record, nil
+}
+
+func (s *idempotencyStore) SaveIdempotencyKeyResponse(_ context.Context, arg db.SaveIdempotencyKeyResponseParams) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+
+	id := db.GetIdempotencyKeyParams{UserID: arg.UserID, Key: arg.Key}
+	record := s.keys[id]
+	record.StatusCode = arg.StatusCode
+	record.ResponseBody = arg.ResponseBody
+	s.keys[id] = record
+	return nil
+}
+
+func (s *idempotencyStore) DeleteIdempotencyKey(_ context.Context, arg db.DeleteIdempotencyKeyParams) error {
+	delete(s.keys, db.GetIdempotencyKeyParams{UserID: arg.UserID, Key: arg.Key})
+	return nil
+}
+
+func (s *idempotencyStore) DeleteExpiredIdempotencyKey(_ context.Context, arg db.DeleteExpiredIdempotencyKeyParams) (int64, error) {
+	id := db.GetIdempotencyKeyParams{UserID: arg.UserID, Key: arg.Key}
+	if record, ok := s.keys[id]; ok && !time.Now().Before(record.ExpiresAt) {
+		delete(s.keys, id)
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// newIdempotencyRouter serves handler on POST /test behind the middleware, requests are authenticated as
+// the user in the X-User-ID header when it is set.
+func newIdempotencyRouter(store db.Store, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(ctx *gin.Context) {
+		if userID, err := strconv.ParseInt(ctx.GetHeader("X-User-ID"), 10, 64); err == nil {
+			ctx.Set(AuthorizationPayloadKey, &token.Payload{UserID: userID})
+		}
+	}
+	router.POST("/test", authenticate, IdempotencyMiddleware(store, time.Hour), handler)
+	return router
+}
+
+func sendIdempotent(router http.Handler, key string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+	request.Header.Set(IdempotencyKeyHeaderKey, key)
+	request.Header.Set("X-User-ID", "1")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestIdempotencyMiddlewareReplay(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(newIdempotencyStore(), func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusCreated, gin.H{"calls": calls})
+	})
+
+	first := sendIdempotent(router, "key", `{"amount":10}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+
+	replayed := sendIdempotent(router, "key", `{"amount":10}`)
+	require.Equal(t, http.StatusCreated, replayed.Code)
+	require.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	require.JSONEq(t, first.Body.String(), replayed.Body.String())
+	require.Equal(t, 1, calls)
+
+	// another key runs the handler again
+	require.Equal(t, http.StatusCreated, sendIdempotent(router, "other", `{"amount":10}`).Code)
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddlewareBodyMismatch(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(newIdempotencyStore(), func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	require.Equal(t, http.StatusCreated, sendIdempotent(router, "key", `{"amount":10}`).Code)
+
+	recorder := sendIdempotent(router, "key", `{"amount":20}`)
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	require.Contains(t, recorder.Body.String(), ErrIdempotencyKeyReused.Error())
+	require.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddlewareInProgress(t *testing.T) {
+	var router *gin.Engine
+	var retried *httptest.ResponseRecorder
+	router = newIdempotencyRouter(newIdempotencyStore(), func(ctx *gin.Context) {
+		// the client retries while the first request is still running
+		if retried == nil {
+			retried = sendIdempotent(router, "key", `{"amount":10}`)
+		}
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	require.Equal(t, http.StatusCreated, sendIdempotent(router, "key", `{"amount":10}`).Code)
+	require.Equal(t, http.StatusConflict, retried.Code)
+	require.Contains(t, retried.Body.String(), ErrIdempotencyKeyInProgress.Error())
+}
+
+func TestIdempotencyMiddlewareReleasesKey(t *testing.T) {
+	store := newIdempotencyStore()
+	status := http.StatusInternalServerError
+	router := newIdempotencyRouter(store, func(ctx *gin.Context) {
+		if status == 0 {
+			panic("handler failed")
+		}
+		ctx.JSON(status, gin.H{})
+	})
+
+	// server errors are not stored
+	require.Equal(t, http.StatusInternalServerError, sendIdempotent(router, "key", `{}`).Code)
+	require.Empty(t, store.keys)
+
+	status = 0
+	require.Panics(t, func() {
+		sendIdempotent(router, "key", `{}`)
+	})
+	require.Empty(t, store.keys)
+
+	status = http.StatusCreated
+	recorder := sendIdempotent(router, "key", `{}`)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Idempotent-Replayed"))
+	require.Len(t, store.keys, 1)
+}
+
+func TestIdempotencyMiddlewareSaveFailed(t *testing.T) {
+	store := newIdempotencyStore()
+	store.saveErr = errors.New("connection reset")
+	calls := 0
+	router := newIdempotencyRouter(store, func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	require.Equal(t, http.StatusCreated, sendIdempotent(router, "key", `{}`).Code)
+	require.Len(t, store.keys, 1)
+
+	// the handler succeeded, a retry must not run it again
+	retried := sendIdempotent(router, "key", `{}`)
+	require.Equal(t, http.StatusConflict, retried.Code)
+	require.Contains(t, retried.Body.String(), ErrIdempotencyKeyInProgress.Error())
+	require.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddlewareUnauthenticated(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(newIdempotencyStore(), func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{}`))
+	request.Header.Set(IdempotencyKeyHeaderKey, "key")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Zero(t, calls)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+)
+
+type updateAccountOverdraftRequest struct {
+	Policy string `json:"policy" binding:"required,oneof=none limit unlimited"`
+	// Limit is how far below zero the balance can go, only with the limit policy.
+	Limit int64 `json:"limit" binding:"min=0"`
+}
+
+// handleUpdateAccountOverdraft sets the overdraft policy of an account, only admins can do it.
+func (s *Server) handleUpdateAccountOverdraft(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req updateAccountOverdraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if !s.authorizeAdmin(ctx, errors.New("only admins can change the overdraft policy")) {
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if account.Kind != db.AccountKindCustomer {
+		err := errors.New("the overdraft policy of system accounts can't be changed")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	account, err := s.store.UpdateOverdraftTx(ctx, db.UpdateAccountOverdraftParams{
+		ID:              account.ID,
+		OverdraftPolicy: req.Policy,
+		OverdraftLimit:  req.Limit,
+	})
+	if errors.Is(err, db.ErrInvalidOverdraft) {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, account)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor points at the last row of a page ordered by (created_at, id),
+// the next page starts right after it.
+type pageCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// encodeCursor turns a cursor into an opaque url safe string, clients must not rely on its format.
+func encodeCursor(cursor pageCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixMicro(), 10) + ":" + strconv.FormatInt(cursor.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	micros, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	cursor := pageCursor{
+		CreatedAt: time.UnixMicro(micros),
+	}
+
+	cursor.ID, err = strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func pageSize(limit int32) int32 {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// sortCursor points at the last row of a page ordered by (Sort, id), Key is the sort column of that row.
+// The sort is part of the cursor so that a cursor of one order can't be used with another.
+type sortCursor struct {
+	Sort string
+	Key  string
+	ID   int64
+}
+
+func encodeSortCursor(cursor sortCursor) string {
+	raw := cursor.Sort + ":" + strconv.FormatInt(cursor.ID, 10) + ":" + cursor.Key
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSortCursor(s string, sort string) (sortCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return sortCursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != sort {
+		return sortCursor{}, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return sortCursor{}, ErrInvalidCursor
+	}
+
+	return sortCursor{Sort: sort, Key: parts[2], ID: id}, nil
+}
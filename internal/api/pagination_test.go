@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPageCursor(t *testing.T) {
+	cursor := pageCursor{
+		CreatedAt: time.Now().Truncate(time.Microsecond),
+		ID:        42,
+	}
+
+	decoded, err := decodeCursor(encodeCursor(cursor))
+	require.NoError(t, err)
+	require.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, s := range []string{"", "!!!", "MTIz", "YWJjOjE", "MTIzOmFiYw"} {
+		_, err := decodeCursor(s)
+		require.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestSortCursor(t *testing.T) {
+	cursor := sortCursor{Sort: "currency", Key: "EUR", ID: 42}
+
+	decoded, err := decodeSortCursor(encodeSortCursor(cursor), "currency")
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+
+	// a cursor is only valid for the order it was made for
+	_, err = decodeSortCursor(encodeSortCursor(cursor), "balance")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	for _, s := range []string{"", "!!!", "Y3VycmVuY3k6RVVS", "Y3VycmVuY3k6YWJjOkVVUg"} {
+		_, err := decodeSortCursor(s, "currency")
+		require.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	require.Equal(t, int32(defaultPageSize), pageSize(0))
+	require.Equal(t, int32(10), pageSize(10))
+	require.Equal(t, int32(maxPageSize), pageSize(maxPageSize+1))
+}
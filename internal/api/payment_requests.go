@@ -0,0 +1,356 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"time"
+)
+
+type createPaymentRequestRequest struct {
+	// Username is the user asked to pay.
+	Username string `json:"username" binding:"required"`
+	// AccountID is the requester's account the money is paid into.
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
+	Currency  string `json:"currency" binding:"required,currency"`
+	Note      string `json:"note" binding:"max=140"`
+}
+
+// handleCreatePaymentRequest asks another user for money, they can pay it from any of their accounts
+// in the same currency until the request expires.
+func (s *Server) handleCreatePaymentRequest(ctx *gin.Context) {
+	var req createPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.validAccount(ctx, req.AccountID, req.Currency)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessSpend); !ok {
+		return
+	}
+
+	if account.Status == db.AccountClosed {
+		handleUnprocessableEntity(ctx, db.ErrAccountClosed)
+		return
+	}
+
+	authPayload := getAuthPayload(ctx)
+	payer, err := s.store.GetUserByUsername(ctx, req.Username)
+	if err == sql.ErrNoRows || payer.Role == util.SystemRole {
+		handleNotFound(ctx, errors.New("user not found"))
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	if payer.ID == authPayload.UserID {
+		handleBadRequest(ctx, errors.New("can't request money from yourself"))
+		return
+	}
+
+	request, err := s.store.CreatePaymentRequest(ctx, db.CreatePaymentRequestParams{
+		RequesterID:        authPayload.UserID,
+		PayerID:            payer.ID,
+		RecipientAccountID: account.ID,
+		Amount:             req.Amount,
+		Currency:           req.Currency,
+		Note:               req.Note,
+		ExpiresAt:          time.Now().Add(s.config.PaymentRequestDuration),
+	})
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, newPaymentRequestResponse(request))
+}
+
+type listPaymentRequestsRequest struct {
+	// Direction is incoming for the requests to pay, outgoing for the requests made, both by default.
+	Direction string `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending accepted declined cancelled expired"`
+	Cursor    string `form:"cursor"`
+	Limit     int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type listPaymentRequestsResponse struct {
+	PaymentRequests []paymentRequestResponse `json:"payment_requests"`
+	NextCursor      string                   `json:"next_cursor,omitempty"`
+}
+
+// handleListPaymentRequests returns the payment requests made by and to the authenticated user, newest first.
+func (s *Server) handleListPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	authPayload := getAuthPayload(ctx)
+
+	limit := pageSize(req.Limit)
+	arg := db.ListPaymentRequestsParams{
+		UserID:   authPayload.UserID,
+		Incoming: req.Direction != "outgoing",
+		Outgoing: req.Direction != "incoming",
+		Status:   sql.NullString{String: req.Status, Valid: req.Status != ""},
+		PageSize: limit + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			handleBadRequest(ctx, err)
+			return
+		}
+		arg.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		arg.CursorID = sql.NullInt64{Int64: cursor.ID, Valid: true}
+	}
+
+	requests, err := s.store.ListPaymentRequests(ctx, arg)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := listPaymentRequestsResponse{
+		PaymentRequests: make([]paymentRequestResponse, 0, len(requests)),
+	}
+
+	if len(requests) > int(limit) {
+		requests = requests[:limit]
+		last := requests[len(requests)-1]
+		res.NextCursor = encodeCursor(pageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	for _, request := range requests {
+		res.PaymentRequests = append(res.PaymentRequests, newPaymentRequestResponse(request))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type getPaymentRequestByIdRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// handleGetPaymentRequest returns a payment request to its requester or its payer.
+func (s *Server) handleGetPaymentRequest(ctx *gin.Context) {
+	var req getPaymentRequestByIdRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	request, ok := s.getPaymentRequest(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := getAuthPayload(ctx)
+	if request.RequesterID != authPayload.UserID && request.PayerID != authPayload.UserID {
+		err := errors.New("payment request doesn't belong to the authenticated user")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newPaymentRequestResponse(request))
+}
+
+type acceptPaymentRequestRequest struct {
+	// AccountID is the payer's account the request is paid from, in the currency of the request.
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+}
+
+// handleAcceptPaymentRequest pays a pending payment request with a transfer to the requester.
+func (s *Server) handleAcceptPaymentRequest(ctx *gin.Context) {
+	var uri getPaymentRequestByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req acceptPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	request, ok := s.getPayerPaymentRequest(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	account, ok := s.validAccount(ctx, req.AccountID, request.Currency)
+	if !ok {
+		return
+	}
+
+	member, ok := s.authorizeSpend(ctx, account, db.TransferTxParams{Amount: request.Amount, Kind: db.TransferKindTransfer})
+	if !ok {
+		return
+	}
+
+	result, err := s.store.AcceptPaymentRequestTx(ctx, db.AcceptPaymentRequestTxParams{
+		RequestID: request.ID,
+		SenderID:  account.ID,
+		CreatedBy: createdBy(member),
+	})
+	if errors.Is(err, db.ErrPaymentRequestNotPending) ||
+		errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrLimitExceeded) ||
+		errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrAccountClosed) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, paymentRequestAcceptResponse{
+		PaymentRequest: newPaymentRequestResponse(result.PaymentRequest),
+		Transfer:       newCreateTransferResponse(result.Transfer),
+	})
+}
+
+// handleDeclinePaymentRequest refuses a pending payment request, only its payer can.
+func (s *Server) handleDeclinePaymentRequest(ctx *gin.Context) {
+	var uri getPaymentRequestByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	request, ok := s.getPayerPaymentRequest(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	request, err := s.store.DeclinePaymentRequestTx(ctx, request.ID)
+	s.handlePaymentRequestClosed(ctx, request, err)
+}
+
+// handleCancelPaymentRequest withdraws a pending payment request, only its requester can.
+func (s *Server) handleCancelPaymentRequest(ctx *gin.Context) {
+	var uri getPaymentRequestByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	request, ok := s.getPaymentRequest(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := getAuthPayload(ctx)
+	if request.RequesterID != authPayload.UserID {
+		err := errors.New("only the requester can cancel a payment request")
+		handleForbidden(ctx, err)
+		return
+	}
+
+	request, err := s.store.CancelPaymentRequestTx(ctx, request.ID)
+	s.handlePaymentRequestClosed(ctx, request, err)
+}
+
+func (s *Server) handlePaymentRequestClosed(ctx *gin.Context, request db.PaymentRequest, err error) {
+	if errors.Is(err, db.ErrPaymentRequestNotPending) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newPaymentRequestResponse(request))
+}
+
+func (s *Server) getPaymentRequest(ctx *gin.Context, requestID int64) (db.PaymentRequest, bool) {
+	request, err := s.store.GetPaymentRequest(ctx, requestID)
+
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return request, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return request, false
+	}
+
+	return request, true
+}
+
+// getPayerPaymentRequest loads a payment request that can be accepted or declined by the authenticated user.
+func (s *Server) getPayerPaymentRequest(ctx *gin.Context, requestID int64) (db.PaymentRequest, bool) {
+	request, ok := s.getPaymentRequest(ctx, requestID)
+	if !ok {
+		return request, false
+	}
+
+	authPayload := getAuthPayload(ctx)
+	if request.PayerID != authPayload.UserID {
+		err := errors.New("only the payer can accept or decline a payment request")
+		handleForbidden(ctx, err)
+		return request, false
+	}
+
+	return request, true
+}
+
+type paymentRequestResponse struct {
+	ID                 int64      `json:"id"`
+	RequesterID        int64      `json:"requester_id"`
+	PayerID            int64      `json:"payer_id"`
+	RecipientAccountID int64      `json:"recipient_account_id"`
+	Amount             int64      `json:"amount"`
+	Currency           string     `json:"currency"`
+	Note               string     `json:"note"`
+	Status             string     `json:"status"`
+	TransferID         int64      `json:"transfer_id,omitempty"`
+	ExpiresAt          time.Time  `json:"expires_at"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+func newPaymentRequestResponse(request db.PaymentRequest) paymentRequestResponse {
+	res := paymentRequestResponse{
+		ID:                 request.ID,
+		RequesterID:        request.RequesterID,
+		PayerID:            request.PayerID,
+		RecipientAccountID: request.RecipientAccountID,
+		Amount:             request.Amount,
+		Currency:           request.Currency,
+		Note:               request.Note,
+		Status:             request.Status,
+		TransferID:         request.TransferID.Int64,
+		ExpiresAt:          request.ExpiresAt,
+		CreatedAt:          request.CreatedAt,
+	}
+	if request.ResolvedAt.Valid {
+		res.ResolvedAt = &request.ResolvedAt.Time
+	}
+	return res
+}
+
+type paymentRequestAcceptResponse struct {
+	PaymentRequest paymentRequestResponse `json:"payment_request"`
+	Transfer       createTransferResponse `json:"transfer"`
+}
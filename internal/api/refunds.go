@@ -0,0 +1,79 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"io"
+)
+
+type refundTransferRequest struct {
+	// Amount is in the currency of the refunding account, the whole remaining amount is refunded by default.
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// handleRefundTransfer sends a transfer, or part of it, back to its sender.
+// Only an owner of the recipient account or an admin can refund a transfer.
+func (s *Server) handleRefundTransfer(ctx *gin.Context) {
+	var uri getTransferByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	// the body is optional, an empty one refunds the whole transfer
+	var req refundTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	transfer, err := s.store.GetTransfer(ctx, uri.ID)
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	recipient, ok := s.getAccount(ctx, transfer.RecipientID)
+	if !ok {
+		return
+	}
+
+	ok, err = s.canAccessAccount(ctx, recipient, accessManage)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	// admins can refund any transfer
+	if !ok && !s.authorizeAdmin(ctx, errors.New("only the recipient of the transfer can refund it")) {
+		return
+	}
+
+	result, err := s.store.RefundTx(ctx, db.RefundTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.Amount,
+	})
+	if errors.Is(err, db.ErrTransferNotRefundable) ||
+		errors.Is(err, db.ErrRefundExceedsTransfer) ||
+		errors.Is(err, db.ErrRefundTooSmall) ||
+		errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrAccountClosed) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, newCreateTransferResponse(result))
+}
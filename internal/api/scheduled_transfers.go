@@ -0,0 +1,388 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"time"
+)
+
+var ErrScheduledTransferFinished = errors.New("scheduled transfer is already completed or cancelled")
+
+type getScheduledTransferRequest struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+	ID        int64 `uri:"scheduled_id" binding:"required,min=1"`
+}
+
+type createScheduledTransferRequest struct {
+	RecipientID int64      `json:"recipient_id" binding:"required,min=1"`
+	Amount      int64      `json:"amount" binding:"required,gt=0"`
+	Currency    string     `json:"currency" binding:"required,currency"`
+	Recurrence  string     `json:"recurrence" binding:"required,recurrence"`
+	StartAt     time.Time  `json:"start_at" binding:"required"`
+	EndAt       *time.Time `json:"end_at"`
+}
+
+// handleCreateScheduledTransfer schedules a transfer from the account, once or repeatedly from start_at until end_at.
+// Scheduled transfers are only supported between accounts in the same currency.
+func (s *Server) handleCreateScheduledTransfer(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if !req.StartAt.After(time.Now()) {
+		err := errors.New("start_at must be in the future")
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.EndAt != nil && req.EndAt.Before(req.StartAt) {
+		err := errors.New("end_at must be after start_at")
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.RecipientID == uri.ID {
+		err := errors.New("recipient account must differ from the sender account")
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	sender, ok := s.validAccount(ctx, uri.ID, req.Currency)
+	if !ok {
+		return
+	}
+
+	member, ok := s.authorizeAccount(ctx, sender, accessSpend)
+	if !ok {
+		return
+	}
+
+	recipient, ok := s.validAccount(ctx, req.RecipientID, req.Currency)
+	if !ok {
+		return
+	}
+
+	// each occurrence is checked against the spend limit on its own
+	debit := db.TransferTxParams{Amount: req.Amount, Kind: db.TransferKindBetween(sender, recipient)}
+	if !s.checkSpendLimit(ctx, member, sender, debit) {
+		return
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		SenderID:    sender.ID,
+		RecipientID: req.RecipientID,
+		Amount:      req.Amount,
+		Recurrence:  req.Recurrence,
+		StartAt:     req.StartAt,
+		NextRunAt:   sql.NullTime{Time: req.StartAt, Valid: true},
+		CreatedBy:   createdBy(member),
+	}
+	if req.EndAt != nil {
+		arg.EndAt = sql.NullTime{Time: *req.EndAt, Valid: true}
+	}
+
+	scheduled, err := s.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleCreated(ctx, newScheduledTransferResponse(scheduled))
+}
+
+func (s *Server) handleListScheduledTransfers(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	scheduled, err := s.store.ListScheduledTransfers(ctx, account.ID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := make([]scheduledTransferResponse, 0, len(scheduled))
+	for _, st := range scheduled {
+		res = append(res, newScheduledTransferResponse(st))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+func (s *Server) handleGetScheduledTransfer(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	scheduled, _, ok := s.getScheduledTransfer(ctx, uri, accessView)
+	if !ok {
+		return
+	}
+
+	handleSuccess(ctx, newScheduledTransferResponse(scheduled))
+}
+
+type updateScheduledTransferRequest struct {
+	Amount *int64     `json:"amount" binding:"omitempty,gt=0"`
+	EndAt  *time.Time `json:"end_at"`
+	Status string     `json:"status" binding:"omitempty,oneof=active paused"`
+}
+
+// handleUpdateScheduledTransfer changes the amount or end date of a scheduled transfer, or pauses and resumes it.
+// A resumed schedule continues with its next occurrence, occurrences missed while paused are skipped.
+func (s *Server) handleUpdateScheduledTransfer(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req updateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	scheduled, member, ok := s.getScheduledTransfer(ctx, uri, accessSpend)
+	if !ok {
+		return
+	}
+
+	if !isScheduledTransferPending(scheduled) {
+		handleUnprocessableEntity(ctx, ErrScheduledTransferFinished)
+		return
+	}
+
+	arg := db.UpdateScheduledTransferParams{
+		ID: scheduled.ID,
+	}
+
+	if req.Amount != nil {
+		if member.SpendLimit.Valid && !s.checkScheduledSpendLimit(ctx, member, scheduled, *req.Amount) {
+			return
+		}
+		arg.Amount = sql.NullInt64{Int64: *req.Amount, Valid: true}
+	}
+
+	if req.EndAt != nil {
+		if req.EndAt.Before(scheduled.StartAt) {
+			err := errors.New("end_at must be after start_at")
+			handleBadRequest(ctx, err)
+			return
+		}
+		arg.EndAt = sql.NullTime{Time: *req.EndAt, Valid: true}
+	}
+
+	if req.Status != "" {
+		arg.Status = sql.NullString{String: req.Status, Valid: true}
+	}
+
+	if req.Status == db.ScheduledTransferActive && scheduled.Status == db.ScheduledTransferPaused {
+		next, ok := util.NextOccurrence(scheduled.Recurrence, scheduled.StartAt, time.Now())
+		if !ok {
+			err := errors.New("scheduled transfer has no occurrence left to resume")
+			handleUnprocessableEntity(ctx, err)
+			return
+		}
+		arg.NextRunAt = sql.NullTime{Time: next, Valid: true}
+	}
+
+	scheduled, err := s.store.UpdateScheduledTransfer(ctx, arg)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newScheduledTransferResponse(scheduled))
+}
+
+// handleCancelScheduledTransfer stops a scheduled transfer for good, its runs are kept.
+func (s *Server) handleCancelScheduledTransfer(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	scheduled, _, ok := s.getScheduledTransfer(ctx, uri, accessSpend)
+	if !ok {
+		return
+	}
+
+	if !isScheduledTransferPending(scheduled) {
+		handleUnprocessableEntity(ctx, ErrScheduledTransferFinished)
+		return
+	}
+
+	scheduled, err := s.store.CancelScheduledTransfer(ctx, scheduled.ID)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, newScheduledTransferResponse(scheduled))
+}
+
+type listScheduledTransferRunsRequest struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// handleListScheduledTransferRuns returns the latest runs of a scheduled transfer, newest first.
+func (s *Server) handleListScheduledTransferRuns(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req listScheduledTransferRunsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	scheduled, _, ok := s.getScheduledTransfer(ctx, uri, accessView)
+	if !ok {
+		return
+	}
+
+	runs, err := s.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               pageSize(req.Limit),
+	})
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := make([]scheduledTransferRunResponse, 0, len(runs))
+	for _, run := range runs {
+		res = append(res, scheduledTransferRunResponse{
+			ID:         run.ID,
+			Attempt:    run.Attempt,
+			Status:     run.Status,
+			TransferID: run.TransferID.Int64,
+			Error:      run.Error.String,
+			CreatedAt:  run.CreatedAt,
+		})
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// getScheduledTransfer loads a scheduled transfer of an account the authenticated user has the access to,
+// along with their membership of the account.
+func (s *Server) getScheduledTransfer(ctx *gin.Context, uri getScheduledTransferRequest, access accountAccess) (db.ScheduledTransfer, db.AccountMember, bool) {
+	var scheduled db.ScheduledTransfer
+
+	account, ok := s.getAccount(ctx, uri.AccountID)
+	if !ok {
+		return scheduled, db.AccountMember{}, false
+	}
+
+	member, ok := s.authorizeAccount(ctx, account, access)
+	if !ok {
+		return scheduled, member, false
+	}
+
+	scheduled, err := s.store.GetScheduledTransfer(ctx, uri.ID)
+	if err == sql.ErrNoRows || (err == nil && scheduled.SenderID != account.ID) {
+		handleNotFound(ctx, sql.ErrNoRows)
+		return scheduled, member, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return scheduled, member, false
+	}
+
+	return scheduled, member, true
+}
+
+// checkScheduledSpendLimit checks a new amount of a scheduled transfer against the spend limit of the member.
+func (s *Server) checkScheduledSpendLimit(ctx *gin.Context, member db.AccountMember, scheduled db.ScheduledTransfer, amount int64) bool {
+	sender, ok := s.getAccount(ctx, scheduled.SenderID)
+	if !ok {
+		return false
+	}
+
+	recipient, ok := s.getAccount(ctx, scheduled.RecipientID)
+	if !ok {
+		return false
+	}
+
+	debit := db.TransferTxParams{Amount: amount, Kind: db.TransferKindBetween(sender, recipient)}
+	return s.checkSpendLimit(ctx, member, sender, debit)
+}
+
+func isScheduledTransferPending(scheduled db.ScheduledTransfer) bool {
+	return scheduled.Status == db.ScheduledTransferActive || scheduled.Status == db.ScheduledTransferPaused
+}
+
+type scheduledTransferResponse struct {
+	ID          int64      `json:"id"`
+	SenderID    int64      `json:"sender_id"`
+	RecipientID int64      `json:"recipient_id"`
+	Amount      int64      `json:"amount"`
+	Recurrence  string     `json:"recurrence"`
+	StartAt     time.Time  `json:"start_at"`
+	EndAt       *time.Time `json:"end_at,omitempty"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	Attempts    int32      `json:"attempts"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newScheduledTransferResponse(scheduled db.ScheduledTransfer) scheduledTransferResponse {
+	res := scheduledTransferResponse{
+		ID:          scheduled.ID,
+		SenderID:    scheduled.SenderID,
+		RecipientID: scheduled.RecipientID,
+		Amount:      scheduled.Amount,
+		Recurrence:  scheduled.Recurrence,
+		StartAt:     scheduled.StartAt,
+		Attempts:    scheduled.Attempts,
+		Status:      scheduled.Status,
+		CreatedAt:   scheduled.CreatedAt,
+	}
+	if scheduled.EndAt.Valid {
+		res.EndAt = &scheduled.EndAt.Time
+	}
+	if scheduled.NextRunAt.Valid {
+		res.NextRunAt = &scheduled.NextRunAt.Time
+	}
+	return res
+}
+
+type scheduledTransferRunResponse struct {
+	ID         int64     `json:"id"`
+	Attempt    int32     `json:"attempt"`
+	Status     string    `json:"status"`
+	TransferID int64     `json:"transfer_id,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -13,6 +13,8 @@ import (
 	"gobank/internal/api/middlewares"
 	"gobank/internal/auth/token"
 	db "gobank/internal/db/sqlc"
+	"gobank/internal/funding"
+	"gobank/internal/jobs"
 	"gobank/internal/util"
 	"log"
 	"net/http"
@@ -27,6 +29,7 @@ type Server struct {
 	router     *gin.Engine
 	tokenMaker token.Maker
 	config     util.Config
+	funding    funding.Source
 }
 
 func NewServer(config util.Config) *Server {
@@ -36,6 +39,7 @@ func NewServer(config util.Config) *Server {
 
 	s.connectToDB()
 	s.addTokenMaker()
+	s.addFundingSource()
 	s.registerValidators()
 	s.setupRouter()
 
@@ -48,16 +52,27 @@ func (s *Server) Run() {
 		Handler: s.router,
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.Every(jobsCtx, s.config.SchedulerInterval, jobs.NewScheduledTransfers(s.store, s.config))
+	go jobs.Every(jobsCtx, s.config.SchedulerInterval, jobs.NewExpiredHolds(s.store))
+	go jobs.Every(jobsCtx, s.config.SchedulerInterval, jobs.NewExpiredPaymentRequests(s.store))
+	go jobs.Every(jobsCtx, s.config.SchedulerInterval, jobs.NewInterest(s.store))
+	if s.config.OverdraftFeeRateBps > 0 {
+		go jobs.Every(jobsCtx, s.config.SchedulerInterval, jobs.NewOverdraftFees(s.store, s.config))
+	}
+
 	go func() {
 		if err := httpServer.ListenAndServe(); err != nil && errors.Is(err, http.ErrServerClosed) {
 			log.Fatal("Server ListenAndServe error")
 		}
 	}()
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	fmt.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -79,15 +94,22 @@ func (s *Server) registerValidators() {
 		if err != nil {
 			log.Fatal("cannot register currency validator: ", err)
 		}
+
+		err = v.RegisterValidation("recurrence", util.RecurrenceValidator)
+		if err != nil {
+			log.Fatal("cannot register recurrence validator: ", err)
+		}
 	}
 }
 
 func (s *Server) setupRouter() {
 	router := gin.New()
 	authMiddleware := middlewares.AuthMiddleware(s.tokenMaker)
+	idempotencyMiddleware := middlewares.IdempotencyMiddleware(s.store, s.config.IdempotencyKeyTTL)
 
 	api := router.Group("/api")
 	{
+		// the auth routes are not idempotent, their responses hold tokens that must not be stored
 		auth := api.Group("/auth")
 		{
 			auth.POST("/sign-up", s.handleSignUp)
@@ -98,15 +120,83 @@ func (s *Server) setupRouter() {
 		accounts := api.Group("/accounts")
 		accounts.Use(authMiddleware)
 		{
+			accounts.GET("", s.handleListAccounts)
 			accounts.GET("/:id", s.handleGetAccountById)
-			accounts.POST("", s.handleCreateAccount)
+			accounts.GET("/:id/transfers", s.handleListAccountTransfers)
+			accounts.GET("/:id/limits", s.handleGetAccountLimits)
+			accounts.GET("/:id/interest", s.handleGetAccountInterest)
+			accounts.GET("/:id/statement", s.handleGetAccountStatement)
+			accounts.GET("/:id/statement/export", s.handleExportAccountStatement)
+			accounts.GET("/:id/members", s.handleListAccountMembers)
+			accounts.GET("/:id/scheduled-transfers", s.handleListScheduledTransfers)
+			accounts.GET("/:id/scheduled-transfers/:scheduled_id", s.handleGetScheduledTransfer)
+			accounts.GET("/:id/scheduled-transfers/:scheduled_id/runs", s.handleListScheduledTransferRuns)
+			accounts.POST("", idempotencyMiddleware, s.handleCreateAccount)
+			accounts.POST("/:id/scheduled-transfers", idempotencyMiddleware, s.handleCreateScheduledTransfer)
+			accounts.POST("/:id/deposits", idempotencyMiddleware, s.handleDeposit)
+			accounts.POST("/:id/withdrawals", idempotencyMiddleware, s.handleWithdrawal)
+			accounts.POST("/:id/members", idempotencyMiddleware, s.handleInviteAccountMember)
+			accounts.POST("/:id/close", s.handleCloseAccount)
+			accounts.POST("/:id/freeze", s.handleFreezeAccount)
+			accounts.POST("/:id/unfreeze", s.handleUnfreezeAccount)
+			accounts.PATCH("/:id", s.handleUpdateAccount)
+			accounts.PUT("/:id/overdraft", s.handleUpdateAccountOverdraft)
+			accounts.PUT("/:id/interest", s.handleUpdateAccountInterest)
+			accounts.PATCH("/:id/scheduled-transfers/:scheduled_id", s.handleUpdateScheduledTransfer)
+			accounts.DELETE("/:id/scheduled-transfers/:scheduled_id", s.handleCancelScheduledTransfer)
+			accounts.DELETE("/:id/members/:user_id", s.handleRemoveAccountMember)
+		}
+
+		invitations := api.Group("/account-invitations")
+		invitations.Use(authMiddleware)
+		{
+			invitations.GET("", s.handleListAccountInvitations)
+			invitations.POST("/:id/accept", s.handleAcceptAccountInvitation)
+			invitations.POST("/:id/decline", s.handleDeclineAccountInvitation)
+		}
+
+		transfers := api.Group("/transfers")
+		transfers.Use(authMiddleware)
+		{
+			transfers.POST("", idempotencyMiddleware, s.handleCreateTransfer)
+			transfers.POST("/preview", s.handlePreviewTransfer)
+			transfers.POST("/batch", idempotencyMiddleware, s.handleCreateTransferBatch)
+			transfers.GET("/batches/:id", s.handleGetTransferBatch)
+			transfers.POST("/:id/refund", idempotencyMiddleware, s.handleRefundTransfer)
+		}
+
+		holds := api.Group("/holds")
+		holds.Use(authMiddleware)
+		{
+			holds.GET("/:id", s.handleGetHold)
+			holds.POST("", idempotencyMiddleware, s.handleAuthorizeHold)
+			holds.POST("/:id/capture", idempotencyMiddleware, s.handleCaptureHold)
+			holds.POST("/:id/void", s.handleVoidHold)
+		}
+
+		paymentRequests := api.Group("/payment-requests")
+		paymentRequests.Use(authMiddleware)
+		{
+			paymentRequests.GET("", s.handleListPaymentRequests)
+			paymentRequests.GET("/:id", s.handleGetPaymentRequest)
+			paymentRequests.POST("", idempotencyMiddleware, s.handleCreatePaymentRequest)
+			paymentRequests.POST("/:id/accept", idempotencyMiddleware, s.handleAcceptPaymentRequest)
+			paymentRequests.POST("/:id/decline", s.handleDeclinePaymentRequest)
+			paymentRequests.POST("/:id/cancel", s.handleCancelPaymentRequest)
+		}
+
+		fx := api.Group("/fx")
+		fx.Use(authMiddleware)
+		{
+			fx.GET("/rates", s.handleGetFxRates)
+			fx.POST("/quotes", idempotencyMiddleware, s.handleCreateFxQuote)
 		}
 
 		users := api.Group("/users")
 		users.Use(authMiddleware)
 		{
 			users.GET("/:id", s.handleGetUserById)
-			users.POST("", s.handleCreateUser)
+			users.POST("", idempotencyMiddleware, s.handleCreateUser)
 		}
 	}
 
@@ -118,7 +208,7 @@ func (s *Server) connectToDB() {
 	if err != nil {
 		log.Fatal("cannot connect to db:", err)
 	}
-	s.store = db.NewSQLStore(conn)
+	s.store = db.NewSQLStore(conn, s.config)
 }
 
 func (s *Server) addTokenMaker() {
@@ -129,6 +219,14 @@ func (s *Server) addTokenMaker() {
 	s.tokenMaker = tokenMaker
 }
 
+func (s *Server) addFundingSource() {
+	source, err := funding.NewSource(s.config.FundingProvider)
+	if err != nil {
+		log.Fatal("cannot create funding source: ", err)
+	}
+	s.funding = source
+}
+
 func getAuthPayload(ctx *gin.Context) *token.Payload {
 	return ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
 }
@@ -152,10 +250,29 @@ func handleCreated(ctx *gin.Context, obj any) {
 	ctx.JSON(http.StatusCreated, obj)
 }
 
+// errorCodes give clients something stabler than the error message to match on.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{db.ErrInsufficientFunds, "insufficient_funds"},
+	{db.ErrLimitExceeded, "limit_exceeded"},
+	{db.ErrAccountFrozen, "account_frozen"},
+	{db.ErrAccountClosed, "account_closed"},
+	{db.ErrAccountNameTaken, "account_name_taken"},
+}
+
 func handleError(ctx *gin.Context, err error, code int) {
-	ctx.JSON(code, gin.H{
+	res := gin.H{
 		"error": err.Error(),
-	})
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			res["code"] = c.code
+			break
+		}
+	}
+	ctx.JSON(code, res)
 }
 
 func handleBadRequest(ctx *gin.Context, err error) {
@@ -170,10 +287,18 @@ func handleForbidden(ctx *gin.Context, err error) {
 	handleError(ctx, err, http.StatusForbidden)
 }
 
+func handleConflict(ctx *gin.Context, err error) {
+	handleError(ctx, err, http.StatusConflict)
+}
+
 func handleUnauthorized(ctx *gin.Context, err error) {
 	handleError(ctx, err, http.StatusUnauthorized)
 }
 
+func handleUnprocessableEntity(ctx *gin.Context, err error) {
+	handleError(ctx, err, http.StatusUnprocessableEntity)
+}
+
 func handleInternalServerError(ctx *gin.Context, err error) {
 	handleError(ctx, err, http.StatusInternalServerError)
 }
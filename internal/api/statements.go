@@ -0,0 +1,223 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/export"
+	"log"
+	"net/http"
+	"time"
+)
+
+type getAccountStatementRequest struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type statementEntryResponse struct {
+	ID                    int64     `json:"id"`
+	TransferID            int64     `json:"transfer_id,omitempty"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id,omitempty"`
+	Memo                  string    `json:"memo,omitempty"`
+	Reference             string    `json:"reference,omitempty"`
+	Amount                int64     `json:"amount"`
+	RunningBalance        int64     `json:"running_balance"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type accountStatementResponse struct {
+	AccountID      int64                    `json:"account_id"`
+	Currency       string                   `json:"currency"`
+	From           time.Time                `json:"from"`
+	To             time.Time                `json:"to"`
+	OpeningBalance int64                    `json:"opening_balance"`
+	ClosingBalance int64                    `json:"closing_balance"`
+	TotalIn        int64                    `json:"total_in"`
+	TotalOut       int64                    `json:"total_out"`
+	Entries        []statementEntryResponse `json:"entries"`
+}
+
+// handleGetAccountStatement returns every entry of the account in [from, to) with the balance after it,
+// to is now by default.
+func (s *Server) handleGetAccountStatement(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req getAccountStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	if !req.From.Before(req.To) {
+		err := errors.New("from must be before to")
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	statement, err := s.store.StatementTx(ctx, db.StatementTxParams{
+		AccountID: account.ID,
+		From:      req.From,
+		To:        req.To,
+	})
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := accountStatementResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		From:           req.From,
+		To:             req.To,
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		TotalIn:        statement.TotalIn,
+		TotalOut:       statement.TotalOut,
+		Entries:        make([]statementEntryResponse, 0, len(statement.Entries)),
+	}
+
+	for _, entry := range statement.Entries {
+		res.Entries = append(res.Entries, statementEntryResponse{
+			ID:                    entry.ID,
+			TransferID:            entry.TransferID.Int64,
+			CounterpartyAccountID: entry.CounterpartyID,
+			Memo:                  entry.Memo,
+			Reference:             entry.Reference,
+			Amount:                entry.Amount,
+			RunningBalance:        entry.RunningBalance,
+			CreatedAt:             entry.CreatedAt,
+		})
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type exportAccountStatementRequest struct {
+	getAccountStatementRequest
+	Format string `form:"format"`
+}
+
+// handleExportAccountStatement streams the statement of [from, to) as csv, ofx or camt.053.
+// The format query parameter takes precedence over the Accept header.
+// Entries are written as they are read, the response is never held in memory as a whole.
+func (s *Server) handleExportAccountStatement(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req exportAccountStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	format, err := export.FormatFromAccept(ctx.GetHeader("Accept"))
+	if req.Format != "" {
+		format, err = export.ParseFormat(req.Format)
+	}
+	if err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	if !req.From.Before(req.To) {
+		err := errors.New("from must be before to")
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	writer, err := export.NewWriter(format, ctx.Writer)
+	if err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	started := false
+	begin := func(summary db.StatementSummary) error {
+		started = true
+
+		ctx.Header("Content-Type", export.ContentType(format))
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=statement-%d.%s", account.ID, export.FileExtension(format)))
+		ctx.Status(http.StatusOK)
+
+		return writer.Begin(export.Statement{
+			AccountID:      account.ID,
+			Currency:       account.Currency,
+			From:           req.From,
+			To:             req.To,
+			GeneratedAt:    time.Now(),
+			OpeningBalance: summary.OpeningBalance,
+			ClosingBalance: summary.ClosingBalance,
+			TotalIn:        summary.TotalIn,
+			TotalOut:       summary.TotalOut,
+			CreditCount:    summary.CreditCount,
+			DebitCount:     summary.DebitCount,
+		})
+	}
+
+	each := func(line db.StatementLine) error {
+		return writer.WriteLine(export.Line{
+			EntryID:               line.ID,
+			TransferID:            line.TransferID.Int64,
+			CounterpartyAccountID: line.CounterpartyID,
+			Memo:                  line.Memo,
+			Reference:             line.Reference,
+			Amount:                line.Amount,
+			Balance:               line.RunningBalance,
+			BookedAt:              line.CreatedAt,
+		})
+	}
+
+	err = s.store.ExportStatementTx(ctx, db.StatementTxParams{
+		AccountID: account.ID,
+		From:      req.From,
+		To:        req.To,
+	}, begin, each)
+	if err == nil {
+		err = writer.End()
+	}
+
+	if err != nil {
+		// once the body is being streamed the status can't change anymore, the client gets a truncated file
+		if started {
+			log.Printf("statement export of account %d failed: %v", account.ID, err)
+			ctx.Abort()
+			return
+		}
+		handleInternalServerError(ctx, err)
+	}
+}
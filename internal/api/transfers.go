@@ -0,0 +1,456 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/fees"
+	"gobank/internal/util"
+	"strings"
+	"time"
+)
+
+type createTransferRequest struct {
+	SenderID    int64 `json:"sender_id" binding:"required,min=1"`
+	RecipientID int64 `json:"recipient_id" binding:"required_without=Recipient,excluded_with=Recipient,omitempty,min=1,nefield=SenderID"`
+	// Recipient is the username or email of the recipient, an alternative to RecipientID.
+	// The money goes to the recipient's account in RecipientCurrency.
+	Recipient string `json:"recipient" binding:"required_without=RecipientID,max=254"`
+	// RecipientCurrency defaults to Currency, it's only used with Recipient.
+	RecipientCurrency string `json:"recipient_currency" binding:"omitempty,currency"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Currency          string `json:"currency" binding:"required,currency"`
+	// QuoteID is required when the recipient account is in another currency than the sender account.
+	QuoteID   string `json:"quote_id" binding:"omitempty,uuid"`
+	Memo      string `json:"memo" binding:"max=280"`
+	Reference string `json:"reference" binding:"max=140"`
+	// Metadata is searchable like the memo and the reference, with up to 20 short string values.
+	Metadata map[string]string `json:"metadata" binding:"max=20,dive,keys,min=1,max=40,endkeys,max=500"`
+}
+
+func (s *Server) handleCreateTransfer(ctx *gin.Context) {
+	_, arg, byName, ok := s.bindTransferRequest(ctx, true)
+	if !ok {
+		return
+	}
+
+	result, err := s.store.TransferTx(ctx, arg)
+	if byName && isTransferRejected(err) {
+		// a rejected transfer to a username or email looks like an unknown recipient,
+		// otherwise the rejection would tell that the user is registered
+		handleNotFound(ctx, errRecipientNotFound)
+		return
+	}
+
+	if isTransferRejected(err) {
+		handleUnprocessableEntity(ctx, err)
+		return
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := newCreateTransferResponse(result)
+	handleCreated(ctx, res)
+}
+
+// isTransferRejected tells if TransferTx refused the transfer without writing it.
+func isTransferRejected(err error) bool {
+	return errors.Is(err, db.ErrQuoteUnavailable) ||
+		errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrLimitExceeded) ||
+		errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrAccountClosed)
+}
+
+type previewTransferResponse struct {
+	Amount          int64      `json:"amount"`
+	RecipientAmount int64      `json:"recipient_amount"`
+	ExchangeRate    string     `json:"exchange_rate,omitempty"`
+	Fees            []fees.Fee `json:"fees"`
+	TotalFee        int64      `json:"total_fee"`
+	// TotalDebit is taken from the sender account, the amount and the fees.
+	TotalDebit int64 `json:"total_debit"`
+}
+
+// handlePreviewTransfer returns the fees of a transfer without making it, the quote isn't used either.
+// A recipient given by username or email isn't looked up, the preview is for a transfer to another user,
+// otherwise the preview could be used to find out who is registered without limits.
+func (s *Server) handlePreviewTransfer(ctx *gin.Context) {
+	sender, arg, _, ok := s.bindTransferRequest(ctx, false)
+	if !ok {
+		return
+	}
+
+	fee := s.store.PreviewTransferFees(sender, arg)
+
+	res := previewTransferResponse{
+		Amount:          arg.Amount,
+		RecipientAmount: arg.Amount,
+		ExchangeRate:    arg.ExchangeRate.String,
+		Fees:            fee.Fees,
+		TotalFee:        fee.Total,
+		TotalDebit:      arg.Amount + fee.Total,
+	}
+	if arg.RecipientAmount > 0 {
+		res.RecipientAmount = arg.RecipientAmount
+	}
+	if res.Fees == nil {
+		res.Fees = []fees.Fee{}
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// bindTransferRequest validates a transfer request from one of the authenticated user's accounts.
+// A recipient given by username or email is only resolved when resolveRecipient is set, after every check
+// of the sender, so an unknown recipient can't be told apart from a request the sender isn't allowed to make.
+// byName tells that the recipient was given by username or email.
+func (s *Server) bindTransferRequest(ctx *gin.Context, resolveRecipient bool) (sender db.Account, arg db.TransferTxParams, byName bool, ok bool) {
+	var req createTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return sender, arg, false, false
+	}
+	byName = req.Recipient != ""
+
+	sender, ok = s.validAccount(ctx, req.SenderID, req.Currency)
+	if !ok {
+		return sender, arg, byName, false
+	}
+
+	member, ok := s.authorizeAccount(ctx, sender, accessSpend)
+	if !ok {
+		return sender, arg, byName, false
+	}
+
+	var recipient db.Account
+	switch {
+	case byName:
+		recipientCurrency := req.RecipientCurrency
+		if recipientCurrency == "" {
+			recipientCurrency = req.Currency
+		}
+		// the account of another user until the recipient is resolved, its fees are the highest
+		recipient = db.Account{Currency: recipientCurrency, Kind: db.AccountKindCustomer}
+	case req.QuoteID == "":
+		recipient, ok = s.validAccount(ctx, req.RecipientID, req.Currency)
+	default:
+		recipient, ok = s.getAccount(ctx, req.RecipientID)
+	}
+	if !ok {
+		return sender, arg, byName, false
+	}
+
+	if recipient.ID == sender.ID {
+		handleBadRequest(ctx, errRecipientIsSender)
+		return sender, arg, byName, false
+	}
+
+	arg = db.TransferTxParams{
+		SenderID:    req.SenderID,
+		RecipientID: recipient.ID,
+		Amount:      req.Amount,
+		Kind:        db.TransferKindBetween(sender, recipient),
+		Memo:        req.Memo,
+		Reference:   req.Reference,
+		CreatedBy:   createdBy(member),
+	}
+
+	if len(req.Metadata) > 0 {
+		metadata, err := json.Marshal(req.Metadata)
+		if err != nil {
+			handleBadRequest(ctx, err)
+			return sender, arg, byName, false
+		}
+		arg.Metadata = metadata
+	}
+
+	if req.QuoteID == "" {
+		if recipient.Currency != req.Currency {
+			err := errors.New("a quote is required to transfer to an account in another currency")
+			handleBadRequest(ctx, err)
+			return sender, arg, byName, false
+		}
+	} else {
+		quote, recipientAmount, ok := s.quotedRecipientAmount(ctx, uuid.MustParse(req.QuoteID), sender, recipient, req.Amount)
+		if !ok {
+			return sender, arg, byName, false
+		}
+
+		arg.RecipientAmount = recipientAmount
+		arg.ExchangeRate = sql.NullString{String: quote.Rate, Valid: true}
+		arg.QuoteID = uuid.NullUUID{UUID: quote.ID, Valid: true}
+	}
+
+	if !s.checkSpendLimit(ctx, member, sender, arg) {
+		return sender, arg, byName, false
+	}
+
+	if byName && resolveRecipient {
+		recipient, ok = s.findRecipientAccount(ctx, req.Recipient, recipient.Currency)
+		if !ok {
+			return sender, arg, byName, false
+		}
+
+		if recipient.ID == sender.ID {
+			handleBadRequest(ctx, errRecipientIsSender)
+			return sender, arg, byName, false
+		}
+
+		arg.RecipientID = recipient.ID
+		arg.Kind = db.TransferKindBetween(sender, recipient)
+	}
+
+	return sender, arg, byName, true
+}
+
+type getTransferByIdRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listAccountTransfersRequest struct {
+	Direction string    `form:"direction" binding:"omitempty,oneof=sent received"`
+	MinAmount int64     `form:"min_amount" binding:"omitempty,min=1"`
+	MaxAmount int64     `form:"max_amount" binding:"omitempty,min=1"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor    string    `form:"cursor"`
+	Limit     int32     `form:"limit" binding:"omitempty,min=1,max=100"`
+	// Q searches the memo, the reference and the metadata values, with the syntax of web search engines.
+	Q string `form:"q" binding:"max=200"`
+}
+
+type listAccountTransfersResponse struct {
+	Transfers  []transferResponse `json:"transfers"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// handleListAccountTransfers returns the transfers sent and received by an account, newest first.
+// Amount filters apply to the amount in the account's currency.
+func (s *Server) handleListAccountTransfers(ctx *gin.Context) {
+	var uri getAccountByIdRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	var req listAccountTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handleBadRequest(ctx, err)
+		return
+	}
+
+	account, ok := s.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.authorizeAccount(ctx, account, accessView); !ok {
+		return
+	}
+
+	limit := pageSize(req.Limit)
+	arg := db.ListAccountTransfersParams{
+		AccountID:       account.ID,
+		IncludeSent:     req.Direction != "received",
+		IncludeReceived: req.Direction != "sent",
+		MinAmount:       sql.NullInt64{Int64: req.MinAmount, Valid: req.MinAmount > 0},
+		MaxAmount:       sql.NullInt64{Int64: req.MaxAmount, Valid: req.MaxAmount > 0},
+		FromTime:        sql.NullTime{Time: req.From, Valid: !req.From.IsZero()},
+		ToTime:          sql.NullTime{Time: req.To, Valid: !req.To.IsZero()},
+		Query:           sql.NullString{String: req.Q, Valid: strings.TrimSpace(req.Q) != ""},
+		PageSize:        limit + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			handleBadRequest(ctx, err)
+			return
+		}
+		arg.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		arg.CursorID = sql.NullInt64{Int64: cursor.ID, Valid: true}
+	}
+
+	transfers, err := s.store.ListAccountTransfers(ctx, arg)
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return
+	}
+
+	res := listAccountTransfersResponse{
+		Transfers: make([]transferResponse, 0, len(transfers)),
+	}
+
+	if len(transfers) > int(limit) {
+		transfers = transfers[:limit]
+		last := transfers[len(transfers)-1]
+		res.NextCursor = encodeCursor(pageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	for _, transfer := range transfers {
+		res.Transfers = append(res.Transfers, newTransferResponse(transfer))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+func (s *Server) getAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := s.store.GetAccount(ctx, accountID)
+
+	if err == sql.ErrNoRows {
+		handleNotFound(ctx, err)
+		return account, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return account, false
+	}
+
+	return account, true
+}
+
+// errRecipientNotFound is the same whether the user doesn't exist, has no account in the currency
+// or the transfer to them was rejected, so the transfer API can't be used to find out who is registered.
+var errRecipientNotFound = errors.New("no account in this currency for this username or email, or the transfer was rejected")
+
+var errRecipientIsSender = errors.New("recipient is the sender account")
+
+// findRecipientAccount resolves a username or email to the customer account of that user in the currency.
+// Emails are matched case-insensitively, an email shared by several users in different cases matches none of them.
+func (s *Server) findRecipientAccount(ctx *gin.Context, recipient string, currency string) (db.Account, bool) {
+	var user db.User
+	var err error
+	if strings.Contains(recipient, "@") {
+		user, err = s.store.GetUserByEmail(ctx, recipient)
+	} else {
+		user, err = s.store.GetUserByUsername(ctx, recipient)
+	}
+
+	var account db.Account
+	if err == nil && user.Role != util.SystemRole {
+		account, err = s.store.GetOwnerAccount(ctx, db.GetOwnerAccountParams{
+			OwnerID:  user.ID,
+			Currency: currency,
+		})
+	}
+
+	if err == sql.ErrNoRows || user.Role == util.SystemRole {
+		handleNotFound(ctx, errRecipientNotFound)
+		return account, false
+	}
+
+	if err != nil {
+		handleInternalServerError(ctx, err)
+		return account, false
+	}
+
+	return account, true
+}
+
+func (s *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
+	account, ok := s.getAccount(ctx, accountID)
+	if !ok {
+		return account, false
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+		handleBadRequest(ctx, err)
+		return account, false
+	}
+
+	return account, true
+}
+
+type transferResponse struct {
+	ID              int64           `json:"id"`
+	SenderID        int64           `json:"sender_id"`
+	RecipientID     int64           `json:"recipient_id"`
+	Amount          int64           `json:"amount"`
+	RecipientAmount int64           `json:"recipient_amount"`
+	ExchangeRate    string          `json:"exchange_rate,omitempty"`
+	QuoteID         *uuid.UUID      `json:"quote_id,omitempty"`
+	Kind            string          `json:"kind"`
+	ParentID        int64           `json:"parent_id,omitempty"`
+	ExternalRef     string          `json:"external_ref,omitempty"`
+	Memo            string          `json:"memo,omitempty"`
+	Reference       string          `json:"reference,omitempty"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+func newTransferResponse(transfer db.Transfer) transferResponse {
+	res := transferResponse{
+		ID:              transfer.ID,
+		SenderID:        transfer.SenderID,
+		RecipientID:     transfer.RecipientID,
+		Amount:          transfer.Amount,
+		RecipientAmount: transfer.RecipientAmount,
+		ExchangeRate:    transfer.ExchangeRate.String,
+		Kind:            transfer.Kind,
+		ParentID:        transfer.ParentID.Int64,
+		ExternalRef:     transfer.ExternalRef.String,
+		Memo:            transfer.Memo,
+		Reference:       transfer.Reference,
+		CreatedAt:       transfer.CreatedAt,
+	}
+	if string(transfer.Metadata) != "{}" {
+		res.Metadata = transfer.Metadata
+	}
+	if transfer.QuoteID.Valid {
+		res.QuoteID = &transfer.QuoteID.UUID
+	}
+	return res
+}
+
+type entryResponse struct {
+	ID         int64     `json:"id"`
+	AccountID  int64     `json:"account_id"`
+	Amount     int64     `json:"amount"`
+	TransferID int64     `json:"transfer_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newEntryResponse(entry db.Entry) entryResponse {
+	return entryResponse{
+		ID:         entry.ID,
+		AccountID:  entry.AccountID,
+		Amount:     entry.Amount,
+		TransferID: entry.TransferID.Int64,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+// createTransferResponse only exposes the sender side of the transfer,
+// the recipient's balance is none of the sender's business.
+type createTransferResponse struct {
+	Transfer      transferResponse  `json:"transfer"`
+	SenderAccount db.Account        `json:"sender_account"`
+	SenderEntry   entryResponse     `json:"sender_entry"`
+	Fees          []fees.Fee        `json:"fees,omitempty"`
+	FeeTransfer   *transferResponse `json:"fee_transfer,omitempty"`
+}
+
+func newCreateTransferResponse(result db.TransferTxResult) createTransferResponse {
+	res := createTransferResponse{
+		Transfer:      newTransferResponse(result.Transfer),
+		SenderAccount: result.SenderAccount,
+		SenderEntry:   newEntryResponse(result.SenderEntry),
+		Fees:          result.Fees,
+	}
+	if result.FeeTransfer != nil {
+		feeTransfer := newTransferResponse(*result.FeeTransfer)
+		res.FeeTransfer = &feeTransfer
+	}
+	return res
+}
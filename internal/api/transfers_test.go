@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"net/http"
+	"testing"
+)
+
+// recipientStore resolves the username "other" to the account of otherID and rejects transfers over the balance.
+type recipientStore struct {
+	*memberStore
+	balance int64
+}
+
+func (s *recipientStore) GetUserByUsername(_ context.Context, username string) (db.User, error) {
+	if username != "other" {
+		return db.User{}, sql.ErrNoRows
+	}
+	return s.users[otherID], nil
+}
+
+func (s *recipientStore) GetOwnerAccount(_ context.Context, arg db.GetOwnerAccountParams) (db.Account, error) {
+	account := s.accounts[otherAccountID]
+	if arg.OwnerID != account.OwnerID || arg.Currency != account.Currency {
+		return db.Account{}, sql.ErrNoRows
+	}
+	return account, nil
+}
+
+func (s *recipientStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	if arg.Amount > s.balance {
+		return db.TransferTxResult{}, db.ErrInsufficientFunds
+	}
+	return s.memberStore.TransferTx(ctx, arg)
+}
+
+func TestCreateTransferToUnknownRecipient(t *testing.T) {
+	s, tokenMaker := newTestServer(t, &recipientStore{memberStore: newMemberStore(), balance: 100})
+
+	send := func(recipient string, amount int64) (int, string) {
+		recorder := sendAs(t, s, tokenMaker, holderID, http.MethodPost, "/api/transfers", gin.H{
+			"sender_id": sharedAccountID,
+			"recipient": recipient,
+			"amount":    amount,
+			"currency":  util.EUR,
+		})
+		return recorder.Code, recorder.Body.String()
+	}
+
+	code, _ := send("other", 50)
+	require.Equal(t, http.StatusCreated, code)
+
+	// a rejected transfer to a registered user answers like an unknown one
+	unknownCode, unknownBody := send("nobody", 500)
+	rejectedCode, rejectedBody := send("other", 500)
+	require.Equal(t, http.StatusNotFound, unknownCode)
+	require.Equal(t, unknownCode, rejectedCode)
+	require.JSONEq(t, unknownBody, rejectedBody)
+
+	// a recipient by id still gets the reason
+	recorder := sendAs(t, s, tokenMaker, holderID, http.MethodPost, "/api/transfers", gin.H{
+		"sender_id":    sharedAccountID,
+		"recipient_id": otherAccountID,
+		"amount":       500,
+		"currency":     util.EUR,
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrInsufficientFunds.Error())
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "entries_transfer_id_idx";
+
+DROP INDEX IF EXISTS "entries_account_id_idx";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "transfer_id";
@@ -0,0 +1,13 @@
+CREATE TABLE "idempotency_keys"
+(
+    "user_id"       bigint      NOT NULL,
+    "key"           varchar     NOT NULL,
+    "request_hash"  varchar     NOT NULL,
+    "status_code"   integer     NOT NULL DEFAULT 0,
+    "response_body" bytea       NOT NULL DEFAULT '',
+    "expires_at"    timestamptz NOT NULL,
+    "created_at"    timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("user_id", "key")
+);
+
+CREATE INDEX ON "idempotency_keys" ("expires_at");
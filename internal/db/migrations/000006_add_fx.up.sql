@@ -0,0 +1,40 @@
+CREATE TABLE "exchange_rates"
+(
+    "id"             bigserial       PRIMARY KEY,
+    "base_currency"  varchar         NOT NULL,
+    "quote_currency" varchar         NOT NULL,
+    "rate"           numeric(20, 10) NOT NULL CHECK ("rate" > 0),
+    "source"         varchar         NOT NULL,
+    "effective_at"   timestamptz     NOT NULL,
+    "created_at"     timestamptz     NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'units of quote currency for one unit of base currency';
+
+CREATE UNIQUE INDEX ON "exchange_rates" ("base_currency", "quote_currency", "effective_at");
+
+CREATE TABLE "fx_quotes"
+(
+    "id"            uuid            PRIMARY KEY,
+    "user_id"       bigint          NOT NULL,
+    "from_currency" varchar         NOT NULL,
+    "to_currency"   varchar         NOT NULL,
+    "rate"          numeric(20, 10) NOT NULL CHECK ("rate" > 0),
+    "expires_at"    timestamptz     NOT NULL,
+    "used_at"       timestamptz,
+    "created_at"    timestamptz     NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "fx_quotes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "recipient_amount" bigint;
+
+UPDATE "transfers" SET "recipient_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "recipient_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20, 10);
+
+ALTER TABLE "transfers" ADD COLUMN "quote_id" uuid UNIQUE;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("quote_id") REFERENCES "fx_quotes" ("id");
@@ -0,0 +1,45 @@
+CREATE TABLE "scheduled_transfers"
+(
+    "id"           bigserial   PRIMARY KEY,
+    "sender_id"    bigint      NOT NULL,
+    "recipient_id" bigint      NOT NULL,
+    "amount"       bigint      NOT NULL CHECK ("amount" > 0),
+    "recurrence"   varchar     NOT NULL,
+    "start_at"     timestamptz NOT NULL,
+    "end_at"       timestamptz,
+    "next_run_at"  timestamptz,
+    "attempts"     integer     NOT NULL DEFAULT 0,
+    "status"       varchar     NOT NULL DEFAULT 'active',
+    "created_at"   timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "scheduled_transfers"."recurrence" IS 'once, daily, weekly, monthly or yearly, counted from start_at';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'null once the schedule is completed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfers"."attempts" IS 'failed attempts of the current occurrence';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("sender_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("recipient_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "scheduled_transfers" ("sender_id");
+
+CREATE INDEX ON "scheduled_transfers" ("next_run_at") WHERE "status" = 'active';
+
+CREATE TABLE "scheduled_transfer_runs"
+(
+    "id"                    bigserial   PRIMARY KEY,
+    "scheduled_transfer_id" bigint      NOT NULL,
+    "attempt"               integer     NOT NULL,
+    "status"                varchar     NOT NULL,
+    "transfer_id"           bigint,
+    "error"                 varchar,
+    "created_at"            timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id");
@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "parent_id";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "kind";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
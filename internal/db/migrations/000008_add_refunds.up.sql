@@ -0,0 +1,11 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "transfers" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'transfer';
+
+ALTER TABLE "transfers" ADD COLUMN "parent_id" bigint;
+
+COMMENT ON COLUMN "transfers"."parent_id" IS 'the transfer this one refunds';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("parent_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfers" ("parent_id");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "holds";
+
+COMMENT ON COLUMN "accounts"."balance" IS NULL;
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "available_balance";
@@ -0,0 +1,34 @@
+ALTER TABLE "accounts" ADD COLUMN "available_balance" bigint;
+
+UPDATE "accounts" SET "available_balance" = "balance";
+
+ALTER TABLE "accounts" ALTER COLUMN "available_balance" SET NOT NULL;
+
+COMMENT ON COLUMN "accounts"."balance" IS 'ledger balance, the sum of the account entries';
+
+COMMENT ON COLUMN "accounts"."available_balance" IS 'ledger balance minus the amount of active holds';
+
+CREATE TABLE "holds"
+(
+    "id"              bigserial   PRIMARY KEY,
+    "account_id"      bigint      NOT NULL,
+    "recipient_id"    bigint      NOT NULL,
+    "amount"          bigint      NOT NULL CHECK ("amount" > 0),
+    "status"          varchar     NOT NULL DEFAULT 'active',
+    "captured_amount" bigint      NOT NULL DEFAULT 0,
+    "transfer_id"     bigint,
+    "expires_at"      timestamptz NOT NULL,
+    "created_at"      timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'the transfer the hold was captured into';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("recipient_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "holds" ("account_id");
+
+CREATE INDEX ON "holds" ("expires_at") WHERE "status" = 'active';
@@ -0,0 +1,24 @@
+CREATE TABLE "transfer_limits"
+(
+    "id"             bigserial   PRIMARY KEY,
+    "user_id"        bigint,
+    "account_id"     bigint,
+    "currency"       varchar     NOT NULL,
+    "per_transfer"   bigint,
+    "daily_amount"   bigint,
+    "monthly_amount" bigint,
+    "daily_count"    bigint,
+    "monthly_count"  bigint,
+    "created_at"     timestamptz NOT NULL DEFAULT (now()),
+    CHECK (("user_id" IS NULL) <> ("account_id" IS NULL))
+);
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("user_id", "currency") WHERE "user_id" IS NOT NULL;
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("account_id") WHERE "account_id" IS NOT NULL;
+
+CREATE INDEX ON "transfers" ("sender_id", "created_at");
@@ -0,0 +1,15 @@
+DELETE FROM "accounts" WHERE "kind" <> 'customer';
+
+DELETE FROM "users" WHERE "username" = 'gobank-system';
+
+DROP INDEX IF EXISTS "system_account_key";
+
+DROP INDEX IF EXISTS "owner_currency_key";
+
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner_id", "currency");
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "overdraft_limit";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "overdraft_policy";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "kind";
@@ -0,0 +1,28 @@
+ALTER TABLE "accounts" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "accounts" ADD COLUMN "overdraft_policy" varchar NOT NULL DEFAULT 'none';
+
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0 CHECK ("overdraft_limit" >= 0);
+
+COMMENT ON COLUMN "accounts"."kind" IS 'customer, or the purpose of a system account, e.g. fees';
+
+COMMENT ON COLUMN "accounts"."overdraft_policy" IS 'none, limit or unlimited';
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the available balance can go under the limit policy';
+
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner_id", "currency") WHERE "kind" = 'customer';
+
+CREATE UNIQUE INDEX "system_account_key" ON "accounts" ("kind", "currency") WHERE "kind" <> 'customer';
+
+CREATE INDEX ON "accounts" ("id") WHERE "balance" < 0;
+
+-- the system user can't sign in, its password isn't a bcrypt hash
+INSERT INTO "users" ("username", "email", "password", "role")
+VALUES ('gobank-system', 'system@gobank.local', '!', 'system');
+
+INSERT INTO "accounts" ("owner_id", "balance", "available_balance", "currency", "kind", "overdraft_policy")
+SELECT "users"."id", 0, 0, "currency", 'fees', 'unlimited'
+FROM "users", unnest(ARRAY ['EUR', 'USD', 'RUB']) AS "currency"
+WHERE "users"."username" = 'gobank-system';
@@ -0,0 +1,3 @@
+DELETE FROM "accounts" WHERE "kind" = 'settlement';
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "external_ref";
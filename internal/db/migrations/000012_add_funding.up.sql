@@ -0,0 +1,10 @@
+ALTER TABLE "transfers" ADD COLUMN "external_ref" varchar;
+
+COMMENT ON COLUMN "transfers"."external_ref" IS 'reference of a deposit or withdrawal at the funding provider';
+
+CREATE UNIQUE INDEX ON "transfers" ("external_ref") WHERE "external_ref" IS NOT NULL;
+
+INSERT INTO "accounts" ("owner_id", "balance", "available_balance", "currency", "kind", "overdraft_policy")
+SELECT "users"."id", 0, 0, "currency", 'settlement', 'unlimited'
+FROM "users", unnest(ARRAY ['EUR', 'USD', 'RUB']) AS "currency"
+WHERE "users"."username" = 'gobank-system';
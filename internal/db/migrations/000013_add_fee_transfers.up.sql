@@ -0,0 +1 @@
+COMMENT ON COLUMN "transfers"."parent_id" IS 'the transfer this one refunds or charges the fees of';
@@ -0,0 +1,7 @@
+DELETE FROM "accounts" WHERE "kind" = 'interest';
+
+DROP TABLE IF EXISTS "interest_accruals";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "interest_accrued_to";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "interest_rate_bps";
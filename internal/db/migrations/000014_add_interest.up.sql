@@ -0,0 +1,41 @@
+ALTER TABLE "accounts" ADD COLUMN "interest_rate_bps" bigint NOT NULL DEFAULT 0 CHECK ("interest_rate_bps" >= 0);
+
+ALTER TABLE "accounts" ADD COLUMN "interest_accrued_to" date;
+
+COMMENT ON COLUMN "accounts"."interest_rate_bps" IS 'yearly interest rate in basis points, accrued daily on the end of day balance';
+
+COMMENT ON COLUMN "accounts"."interest_accrued_to" IS 'interest is accrued for the days before this one, null without an interest rate';
+
+CREATE TABLE "interest_accruals"
+(
+    "id"             bigserial       PRIMARY KEY,
+    "account_id"     bigint          NOT NULL,
+    "day"            date            NOT NULL,
+    "balance"        bigint          NOT NULL,
+    "rate_bps"       bigint          NOT NULL,
+    "amount"         numeric(30, 10) NOT NULL,
+    "transfer_id"    bigint,
+    "capitalized_at" timestamptz,
+    "created_at"     timestamptz     NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end of day balance the interest is computed on';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest in fractional minor units, rounded once the month is capitalized';
+
+COMMENT ON COLUMN "interest_accruals"."transfer_id" IS 'the interest transfer that paid the accrual, null if it rounded to zero';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "day");
+
+CREATE INDEX ON "interest_accruals" ("day") WHERE "capitalized_at" IS NULL;
+
+CREATE INDEX ON "accounts" ("interest_accrued_to") WHERE "interest_rate_bps" > 0;
+
+INSERT INTO "accounts" ("owner_id", "balance", "available_balance", "currency", "kind", "overdraft_policy")
+SELECT "users"."id", 0, 0, "currency", 'interest', 'unlimited'
+FROM "users", unnest(ARRAY ['EUR', 'USD', 'RUB']) AS "currency"
+WHERE "users"."username" = 'gobank-system';
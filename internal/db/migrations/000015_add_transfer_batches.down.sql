@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "batch_id";
+
+DROP TABLE IF EXISTS "transfer_batch_lines";
+
+DROP TABLE IF EXISTS "transfer_batches";
@@ -0,0 +1,45 @@
+CREATE TABLE "transfer_batches"
+(
+    "id"           bigserial   PRIMARY KEY,
+    "sender_id"    bigint      NOT NULL,
+    "currency"     varchar     NOT NULL,
+    "status"       varchar     NOT NULL,
+    "line_count"   bigint      NOT NULL,
+    "total_amount" bigint      NOT NULL,
+    "total_fee"    bigint      NOT NULL DEFAULT 0,
+    "error"        varchar,
+    "created_at"   timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "transfer_batches"."status" IS 'completed, or rejected when no line was executed';
+
+CREATE TABLE "transfer_batch_lines"
+(
+    "id"           bigserial PRIMARY KEY,
+    "batch_id"     bigint    NOT NULL,
+    "line"         bigint    NOT NULL,
+    "recipient_id" bigint    NOT NULL,
+    "amount"       bigint    NOT NULL,
+    "transfer_id"  bigint,
+    "error"        varchar
+);
+
+COMMENT ON COLUMN "transfer_batch_lines"."line" IS 'position of the line in the batch, from 1';
+
+COMMENT ON COLUMN "transfer_batch_lines"."recipient_id" IS 'as requested, it may not be an existing account in a rejected batch';
+
+ALTER TABLE "transfer_batches" ADD FOREIGN KEY ("sender_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_batch_lines" ADD FOREIGN KEY ("batch_id") REFERENCES "transfer_batches" ("id");
+
+ALTER TABLE "transfer_batch_lines" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE UNIQUE INDEX ON "transfer_batch_lines" ("batch_id", "line");
+
+CREATE INDEX ON "transfer_batches" ("sender_id");
+
+ALTER TABLE "transfers" ADD COLUMN "batch_id" bigint;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("batch_id") REFERENCES "transfer_batches" ("id");
+
+CREATE INDEX ON "transfers" ("batch_id");
@@ -0,0 +1,36 @@
+CREATE TABLE "payment_requests"
+(
+    "id"                   bigserial   PRIMARY KEY,
+    "requester_id"         bigint      NOT NULL,
+    "payer_id"             bigint      NOT NULL,
+    "recipient_account_id" bigint      NOT NULL,
+    "amount"               bigint      NOT NULL CHECK ("amount" > 0),
+    "currency"             varchar     NOT NULL,
+    "note"                 varchar     NOT NULL DEFAULT '',
+    "status"               varchar     NOT NULL DEFAULT 'pending',
+    "transfer_id"          bigint,
+    "expires_at"           timestamptz NOT NULL,
+    "resolved_at"          timestamptz,
+    "created_at"           timestamptz NOT NULL DEFAULT (now()),
+    CHECK ("requester_id" <> "payer_id")
+);
+
+COMMENT ON COLUMN "payment_requests"."recipient_account_id" IS 'account of the requester the money is paid into';
+
+COMMENT ON COLUMN "payment_requests"."status" IS 'pending, accepted, declined, cancelled or expired';
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'the transfer that paid the request once accepted';
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester_id") REFERENCES "users" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer_id") REFERENCES "users" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("recipient_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "payment_requests" ("payer_id", "created_at");
+
+CREATE INDEX ON "payment_requests" ("requester_id", "created_at");
+
+CREATE INDEX ON "payment_requests" ("expires_at") WHERE "status" = 'pending';
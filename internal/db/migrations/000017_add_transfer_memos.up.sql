@@ -0,0 +1,15 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '' CHECK (char_length("memo") <= 280);
+
+ALTER TABLE "transfers" ADD COLUMN "reference" varchar NOT NULL DEFAULT '' CHECK (char_length("reference") <= 140);
+
+ALTER TABLE "transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}' CHECK (jsonb_typeof("metadata") = 'object' AND pg_column_size("metadata") <= 16384);
+
+COMMENT ON COLUMN "transfers"."memo" IS 'free text of the sender, what the payment is for';
+
+COMMENT ON COLUMN "transfers"."reference" IS 'reference of the sender, e.g. an invoice number, unlike external_ref it is not unique';
+
+COMMENT ON COLUMN "transfers"."metadata" IS 'flat object of string values set by the sender';
+
+CREATE INDEX ON "transfers" USING GIN (
+    (to_tsvector('simple', "memo" || ' ' || "reference") || jsonb_to_tsvector('simple', "metadata", '["string"]'))
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner_id", "currency") WHERE "kind" = 'customer';
+
+DROP TABLE IF EXISTS "account_status_changes";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,26 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed, only active accounts can send money';
+
+CREATE TABLE "account_status_changes"
+(
+    "id"          bigserial   PRIMARY KEY,
+    "account_id"  bigint      NOT NULL,
+    "from_status" varchar     NOT NULL,
+    "to_status"   varchar     NOT NULL,
+    "reason"      varchar     NOT NULL DEFAULT '',
+    "changed_by"  bigint      NOT NULL,
+    "created_at"  timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "account_status_changes"."changed_by" IS 'the user who changed the status, the owner or an admin';
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("changed_by") REFERENCES "users" ("id");
+
+CREATE INDEX ON "account_status_changes" ("account_id");
+
+DROP INDEX "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner_id", "currency") WHERE "kind" = 'customer' AND "status" <> 'closed';
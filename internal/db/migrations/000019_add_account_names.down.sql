@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "owner_primary_key";
+
+DROP INDEX IF EXISTS "owner_name_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner_id", "currency") WHERE "kind" = 'customer' AND "status" <> 'closed';
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "is_primary";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "purpose";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "name";
@@ -0,0 +1,23 @@
+ALTER TABLE "accounts" ADD COLUMN "name" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "purpose" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "is_primary" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "accounts"."name" IS 'nickname of the account, unique among the open accounts of its owner';
+
+COMMENT ON COLUMN "accounts"."purpose" IS 'what the owner keeps the money of the account for, e.g. savings';
+
+COMMENT ON COLUMN "accounts"."is_primary" IS 'the account money sent to the owner by username or email goes to, at most one per currency';
+
+-- until now every owner had a single account per currency
+UPDATE "accounts"
+SET "name" = "currency",
+    "is_primary" = "status" <> 'closed'
+WHERE "kind" = 'customer';
+
+DROP INDEX "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_name_key" ON "accounts" ("owner_id", lower("name")) WHERE "kind" = 'customer' AND "status" <> 'closed';
+
+CREATE UNIQUE INDEX "owner_primary_key" ON "accounts" ("owner_id", "currency") WHERE "is_primary" AND "status" <> 'closed';
@@ -0,0 +1,34 @@
+CREATE TABLE "account_members"
+(
+    "id"          bigserial   PRIMARY KEY,
+    "account_id"  bigint      NOT NULL,
+    "user_id"     bigint      NOT NULL,
+    "role"        varchar     NOT NULL,
+    "spend_limit" bigint CHECK ("spend_limit" > 0),
+    "status"      varchar     NOT NULL DEFAULT 'invited',
+    "invited_by"  bigint,
+    "created_at"  timestamptz NOT NULL DEFAULT (now()),
+    "accepted_at" timestamptz
+);
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, spender or viewer';
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spender can send from the account at once, null for no limit';
+
+COMMENT ON COLUMN "account_members"."status" IS 'invited until the user accepts, then active';
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("id");
+
+CREATE UNIQUE INDEX "account_member_key" ON "account_members" ("account_id", "user_id");
+
+CREATE INDEX ON "account_members" ("user_id");
+
+-- the holder of every customer account is its first owner
+INSERT INTO "account_members" ("account_id", "user_id", "role", "status", "accepted_at")
+SELECT "id", "owner_id", 'owner', 'active', "created_at"
+FROM "accounts"
+WHERE "kind" = 'customer';
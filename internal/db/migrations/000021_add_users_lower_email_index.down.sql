@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "users_lower_email_idx";
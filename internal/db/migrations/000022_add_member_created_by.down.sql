@@ -0,0 +1,5 @@
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spender can send from the account at once, null for no limit';
+
+ALTER TABLE "holds" DROP COLUMN IF EXISTS "created_by";
+
+ALTER TABLE "scheduled_transfers" DROP COLUMN IF EXISTS "created_by";
@@ -0,0 +1,17 @@
+ALTER TABLE "scheduled_transfers" ADD COLUMN "created_by" bigint;
+
+ALTER TABLE "holds" ADD COLUMN "created_by" bigint;
+
+COMMENT ON COLUMN "scheduled_transfers"."created_by" IS 'the account member who scheduled the transfer, it is cancelled when they are removed';
+
+COMMENT ON COLUMN "holds"."created_by" IS 'the account member who authorized the hold, it is voided when they are removed';
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spender can send from the account at once, fees included, null for no limit';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+CREATE INDEX ON "scheduled_transfers" ("sender_id", "created_by") WHERE "status" IN ('active', 'paused');
+
+CREATE INDEX ON "holds" ("account_id", "created_by") WHERE "status" = 'active';
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spender can send from the account at once, fees included, null for no limit';
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "created_by";
@@ -0,0 +1,9 @@
+ALTER TABLE "transfers" ADD COLUMN "created_by" bigint;
+
+COMMENT ON COLUMN "transfers"."created_by" IS 'the account member who made the transfer, null for transfers made by the system';
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'largest amount a spender can send from the account per UTC day, fees and holds included, null for no limit';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+CREATE INDEX ON "transfers" ("sender_id", "created_by", "created_at") WHERE "created_by" IS NOT NULL;
@@ -8,7 +8,127 @@ INSERT INTO accounts
 (
     owner_id,
     balance,
-    currency
+    available_balance,
+    currency,
+    name,
+    purpose,
+    is_primary
 )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetOwnerAccount :one
+SELECT * FROM accounts
+WHERE owner_id = $1 AND currency = $2 AND kind = 'customer' AND status <> 'closed'
+ORDER BY is_primary DESC, id
+LIMIT 1;
+
+-- name: ListOwnerAccounts :many
+SELECT * FROM accounts
+WHERE id IN (
+    SELECT account_id FROM account_members
+    WHERE user_id = sqlc.arg(owner_id) AND status = 'active'
+  )
+  AND kind = 'customer'
+  AND (sqlc.narg(currency)::varchar IS NULL OR currency = sqlc.narg(currency))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL OR CASE sqlc.arg(sort)::text
+    WHEN 'balance' THEN (balance, id) < (sqlc.narg(cursor_balance)::bigint, sqlc.narg(cursor_id))
+    WHEN 'currency' THEN (currency, id) > (sqlc.narg(cursor_currency)::varchar, sqlc.narg(cursor_id))
+    WHEN 'created_at' THEN (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id))
+  END)
+ORDER BY CASE WHEN sqlc.arg(sort) = 'balance' THEN balance END DESC,
+         CASE WHEN sqlc.arg(sort) = 'currency' THEN currency END,
+         CASE WHEN sqlc.arg(sort) = 'currency' THEN id END,
+         CASE WHEN sqlc.arg(sort) = 'created_at' THEN created_at END DESC,
+         id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListOwnerAccountTotals :many
+SELECT currency,
+       COUNT(*) AS accounts,
+       SUM(balance)::bigint AS balance,
+       SUM(available_balance)::bigint AS available_balance
+FROM accounts
+WHERE id IN (
+    SELECT account_id FROM account_members
+    WHERE user_id = sqlc.arg(owner_id) AND status = 'active'
+  )
+  AND kind = 'customer'
+  AND (sqlc.narg(currency)::varchar IS NULL OR currency = sqlc.narg(currency))
+GROUP BY currency
+ORDER BY currency;
+
+-- name: ListAccountsByIDs :many
+SELECT * FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddAccountBalance :one
+UPDATE accounts
+SET balance = balance + sqlc.arg(amount),
+    available_balance = available_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddAccountAvailableBalance :one
+UPDATE accounts
+SET available_balance = available_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+
+-- name: GetSystemAccount :one
+SELECT * FROM accounts
+WHERE kind = sqlc.arg(kind) AND currency = sqlc.arg(currency)
+LIMIT 1;
+
+-- name: UpdateAccountOverdraft :one
+UPDATE accounts
+SET overdraft_policy = $2,
+    overdraft_limit = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: ClaimOverdrawnAccount :one
+SELECT * FROM accounts
+WHERE kind = 'customer'
+  AND balance < 0
+  AND NOT EXISTS (
+    SELECT 1 FROM transfers
+    WHERE transfers.sender_id = accounts.id
+      AND transfers.kind = 'overdraft_fee'
+      AND transfers.created_at >= sqlc.arg(day_start)
+  )
+  AND id <> ALL(COALESCE(sqlc.arg(skipped_ids)::bigint[], '{}'))
+ORDER BY id
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountName :one
+UPDATE accounts
+SET name = COALESCE(sqlc.narg(name), name),
+    purpose = COALESCE(sqlc.narg(purpose), purpose)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ClearPrimaryAccount :exec
+UPDATE accounts
+SET is_primary = false
+WHERE owner_id = $1 AND currency = $2 AND is_primary;
+
+-- name: SetPrimaryAccount :one
+UPDATE accounts
+SET is_primary = true
+WHERE id = $1
 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,44 @@
+-- name: CreateAccountMember :one
+INSERT INTO account_members
+(
+    account_id,
+    user_id,
+    role,
+    spend_limit,
+    status,
+    invited_by,
+    accepted_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $5::varchar = 'active' THEN now() END)
+RETURNING *;
+
+-- name: GetAccountMember :one
+SELECT * FROM account_members
+WHERE account_id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: GetAccountMemberByID :one
+SELECT * FROM account_members
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListAccountMembers :many
+SELECT * FROM account_members
+WHERE account_id = $1
+ORDER BY id;
+
+-- name: ListUserInvitations :many
+SELECT * FROM account_members
+WHERE user_id = $1 AND status = 'invited'
+ORDER BY id;
+
+-- name: AcceptAccountMember :one
+UPDATE account_members
+SET status = 'active',
+    accepted_at = now()
+WHERE id = $1 AND status = 'invited'
+RETURNING *;
+
+-- name: DeleteAccountMember :exec
+DELETE FROM account_members
+WHERE id = $1;
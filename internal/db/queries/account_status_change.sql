@@ -0,0 +1,11 @@
+-- name: CreateAccountStatusChange :one
+INSERT INTO account_status_changes
+(
+    account_id,
+    from_status,
+    to_status,
+    reason,
+    changed_by
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
\ No newline at end of file
@@ -1,4 +1,66 @@
 -- name: GetEntry :one
 SELECT * FROM entries
 WHERE id = $1
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: CreateEntry :one
+INSERT INTO entries
+(
+    account_id,
+    amount,
+    transfer_id
+)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+
+-- name: GetAccountBalanceAt :one
+SELECT (a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(at)
+), 0))::bigint AS balance
+FROM accounts a
+WHERE a.id = sqlc.arg(account_id);
+
+-- name: ListStatementEntries :many
+SELECT e.id,
+       e.amount,
+       e.transfer_id,
+       e.created_at,
+       COALESCE(CASE WHEN t.sender_id = e.account_id THEN t.recipient_id ELSE t.sender_id END, 0)::bigint AS counterparty_id,
+       COALESCE(t.memo, '')::varchar AS memo,
+       COALESCE(t.reference, '')::varchar AS reference,
+       (sqlc.arg(opening_balance)::bigint + SUM(e.amount) OVER (ORDER BY e.created_at, e.id))::bigint AS running_balance
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)
+  AND e.created_at < sqlc.arg(to_time)
+ORDER BY e.created_at, e.id;
+
+-- name: GetStatementTotals :one
+SELECT COUNT(*) FILTER (WHERE amount > 0) AS credit_count,
+       COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0)::bigint AS total_in,
+       COUNT(*) FILTER (WHERE amount < 0) AS debit_count,
+       COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0)::bigint AS total_out
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time);
+
+-- name: ListStatementEntriesPage :many
+SELECT e.id,
+       e.amount,
+       e.transfer_id,
+       e.created_at,
+       COALESCE(CASE WHEN t.sender_id = e.account_id THEN t.recipient_id ELSE t.sender_id END, 0)::bigint AS counterparty_id,
+       COALESCE(t.memo, '')::varchar AS memo,
+       COALESCE(t.reference, '')::varchar AS reference
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)
+  AND e.created_at < sqlc.arg(to_time)
+  AND (e.created_at, e.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY e.created_at, e.id
+LIMIT sqlc.arg(page_size);
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates
+(
+    base_currency,
+    quote_currency,
+    rate,
+    source,
+    effective_at
+)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (base_currency, quote_currency, effective_at)
+DO UPDATE SET rate   = EXCLUDED.rate,
+              source = EXCLUDED.source
+RETURNING *;
+
+-- name: ListEffectiveExchangeRates :many
+SELECT DISTINCT ON (base_currency, quote_currency) *
+FROM exchange_rates
+WHERE effective_at <= sqlc.arg(at)
+ORDER BY base_currency, quote_currency, effective_at DESC;
\ No newline at end of file
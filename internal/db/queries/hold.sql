@@ -0,0 +1,43 @@
+-- name: CreateHold :one
+INSERT INTO holds
+(
+    account_id,
+    recipient_id,
+    amount,
+    expires_at,
+    created_by
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListMemberActiveHolds :many
+SELECT * FROM holds
+WHERE account_id = $1 AND created_by = $2 AND status = 'active'
+ORDER BY id
+FOR NO KEY UPDATE;
+
+-- name: ClaimExpiredHold :one
+SELECT * FROM holds
+WHERE status = 'active' AND expires_at <= sqlc.arg(now)
+ORDER BY expires_at
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: UpdateHoldStatus :one
+UPDATE holds
+SET status = sqlc.arg(status),
+    captured_amount = sqlc.arg(captured_amount),
+    transfer_id = sqlc.narg(transfer_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys
+(
+    user_id,
+    key,
+    request_hash,
+    expires_at
+)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE user_id = $1 AND key = $2
+LIMIT 1;
+
+-- name: SaveIdempotencyKeyResponse :exec
+UPDATE idempotency_keys
+SET status_code   = $3,
+    response_body = $4
+WHERE user_id = $1 AND key = $2;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2;
+
+-- name: DeleteExpiredIdempotencyKey :execrows
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 AND expires_at <= now();
\ No newline at end of file
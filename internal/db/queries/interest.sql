@@ -0,0 +1,53 @@
+-- name: SetAccountInterestRate :one
+UPDATE accounts
+SET interest_rate_bps = sqlc.arg(interest_rate_bps),
+    interest_accrued_to = CASE
+        WHEN sqlc.arg(interest_rate_bps)::bigint = 0 THEN NULL
+        ELSE COALESCE(interest_accrued_to, sqlc.arg(today))
+    END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ClaimInterestAccount :one
+SELECT * FROM accounts
+WHERE interest_rate_bps > 0 AND interest_accrued_to < sqlc.arg(today)
+ORDER BY id
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: SetAccountInterestAccruedTo :one
+UPDATE accounts
+SET interest_accrued_to = sqlc.arg(interest_accrued_to)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals
+(
+    account_id,
+    day,
+    balance,
+    rate_bps,
+    amount
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetUncapitalizedInterestAccount :one
+SELECT account_id FROM interest_accruals
+WHERE capitalized_at IS NULL AND day < sqlc.arg(before)
+  AND account_id <> ALL(COALESCE(sqlc.arg(skipped_ids)::bigint[], '{}'))
+ORDER BY account_id
+LIMIT 1;
+
+-- name: GetUncapitalizedInterest :one
+SELECT COUNT(*) AS days,
+       COALESCE(SUM(amount), 0)::numeric AS amount
+FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id) AND capitalized_at IS NULL AND day < sqlc.arg(before);
+
+-- name: CapitalizeInterestAccruals :exec
+UPDATE interest_accruals
+SET capitalized_at = now(),
+    transfer_id = sqlc.narg(transfer_id)
+WHERE account_id = sqlc.arg(account_id) AND capitalized_at IS NULL AND day < sqlc.arg(before);
\ No newline at end of file
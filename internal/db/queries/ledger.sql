@@ -0,0 +1,73 @@
+-- name: ListAccountLedgerBalances :many
+SELECT a.id,
+       a.currency,
+       a.balance,
+       a.available_balance,
+       (SELECT COALESCE(SUM(e.amount), 0) FROM entries e WHERE e.account_id = a.id)::bigint AS entries_balance,
+       (SELECT COALESCE(SUM(h.amount), 0) FROM holds h WHERE h.account_id = a.id AND h.status = 'active')::bigint AS held_amount
+FROM accounts a
+WHERE a.id > sqlc.arg(after_id)
+ORDER BY a.id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListOrphanEntries :many
+SELECT e.id, e.account_id, e.amount, e.created_at, e.transfer_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id > sqlc.arg(after_account_id)
+  AND e.account_id <= sqlc.arg(last_account_id)
+  AND (t.id IS NULL OR e.account_id NOT IN (t.sender_id, t.recipient_id))
+ORDER BY e.id;
+
+-- name: ListUnbalancedTransfers :many
+SELECT t.id,
+       t.sender_id,
+       t.recipient_id,
+       t.amount,
+       t.recipient_amount,
+       COUNT(e.id) AS entry_count,
+       COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.sender_id), 0)::bigint AS sender_entries_amount,
+       COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.recipient_id), 0)::bigint AS recipient_entries_amount
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.sender_id > sqlc.arg(after_account_id)
+  AND t.sender_id <= sqlc.arg(last_account_id)
+GROUP BY t.id
+HAVING COUNT(e.id) <> 2
+    OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.sender_id), 0) <> -t.amount
+    OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.recipient_id), 0) <> t.recipient_amount
+ORDER BY t.id;
+
+-- name: ListCurrencyConversions :many
+SELECT t.id,
+       t.sender_id,
+       t.recipient_id,
+       t.amount,
+       t.recipient_amount,
+       t.exchange_rate,
+       t.kind,
+       s.currency AS sender_currency,
+       r.currency AS recipient_currency
+FROM transfers t
+JOIN accounts s ON s.id = t.sender_id
+JOIN accounts r ON r.id = t.recipient_id
+WHERE t.sender_id > sqlc.arg(after_account_id)
+  AND t.sender_id <= sqlc.arg(last_account_id)
+  AND (s.currency <> r.currency OR t.amount <> t.recipient_amount OR t.exchange_rate IS NOT NULL)
+ORDER BY t.id;
+
+-- name: ListOverRefundedTransfers :many
+SELECT p.id,
+       p.sender_id,
+       p.amount,
+       p.recipient_amount,
+       SUM(r.amount)::bigint AS refunded_amount,
+       SUM(r.recipient_amount)::bigint AS refunded_recipient_amount
+FROM transfers p
+JOIN transfers r ON r.parent_id = p.id AND r.kind = 'refund'
+WHERE p.sender_id > sqlc.arg(after_account_id)
+  AND p.sender_id <= sqlc.arg(last_account_id)
+GROUP BY p.id
+HAVING SUM(r.amount) > p.recipient_amount
+    OR SUM(r.recipient_amount) > p.amount
+ORDER BY p.id;
\ No newline at end of file
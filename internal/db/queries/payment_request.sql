@@ -0,0 +1,52 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests
+(
+    requester_id,
+    payer_id,
+    recipient_account_id,
+    amount,
+    currency,
+    note,
+    expires_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE ((payer_id = sqlc.arg(user_id) AND sqlc.arg(incoming)::boolean) OR (requester_id = sqlc.arg(user_id) AND sqlc.arg(outgoing)::boolean))
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ResolvePaymentRequest :one
+UPDATE payment_requests
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    resolved_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelAccountPaymentRequests :exec
+UPDATE payment_requests
+SET status = 'cancelled',
+    resolved_at = now()
+WHERE recipient_account_id = $1 AND status = 'pending';
+
+-- name: ExpirePaymentRequests :execrows
+UPDATE payment_requests
+SET status = 'expired',
+    resolved_at = sqlc.arg(now)
+WHERE status = 'pending' AND expires_at <= sqlc.arg(now);
\ No newline at end of file
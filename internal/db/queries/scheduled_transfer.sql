@@ -0,0 +1,91 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers
+(
+    sender_id,
+    recipient_id,
+    amount,
+    recurrence,
+    start_at,
+    end_at,
+    next_run_at,
+    created_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE sender_id = $1
+ORDER BY id;
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET amount = COALESCE(sqlc.narg(amount), amount),
+    end_at = COALESCE(sqlc.narg(end_at), end_at),
+    status = COALESCE(sqlc.narg(status), status),
+    next_run_at = COALESCE(sqlc.narg(next_run_at), next_run_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelScheduledTransfer :one
+UPDATE scheduled_transfers
+SET status = 'cancelled',
+    next_run_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelAccountScheduledTransfers :exec
+UPDATE scheduled_transfers
+SET status = 'cancelled',
+    next_run_at = NULL
+WHERE (sender_id = $1 OR recipient_id = $1) AND status = 'active';
+
+-- name: CancelMemberScheduledTransfers :exec
+UPDATE scheduled_transfers
+SET status = 'cancelled',
+    next_run_at = NULL
+WHERE sender_id = $1 AND created_by = $2 AND status IN ('active', 'paused');
+
+-- name: ClaimDueScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE status = 'active' AND next_run_at <= sqlc.arg(now)
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: SetScheduledTransferNextRun :one
+UPDATE scheduled_transfers
+SET next_run_at = sqlc.narg(next_run_at),
+    attempts = sqlc.arg(attempts),
+    status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs
+(
+    scheduled_transfer_id,
+    attempt,
+    status,
+    transfer_id,
+    error
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY id DESC
+LIMIT $2;
\ No newline at end of file
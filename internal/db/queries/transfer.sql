@@ -1,4 +1,82 @@
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: CreateTransfer :one
+INSERT INTO transfers
+(
+    sender_id,
+    recipient_id,
+    amount,
+    recipient_amount,
+    exchange_rate,
+    quote_id,
+    kind,
+    parent_id,
+    external_ref,
+    batch_id,
+    memo,
+    reference,
+    metadata,
+    created_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING *;
+
+
+-- name: ListAccountTransfers :many
+SELECT * FROM (
+    SELECT * FROM transfers
+    WHERE sender_id = sqlc.arg(account_id) AND sqlc.arg(include_sent)::boolean
+    UNION ALL
+    SELECT * FROM transfers
+    WHERE recipient_id = sqlc.arg(account_id) AND sqlc.arg(include_received)::boolean
+) AS t
+WHERE (sqlc.narg(min_amount)::bigint IS NULL OR CASE WHEN t.sender_id = sqlc.arg(account_id) THEN t.amount ELSE t.recipient_amount END >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR CASE WHEN t.sender_id = sqlc.arg(account_id) THEN t.amount ELSE t.recipient_amount END <= sqlc.narg(max_amount))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(query)::text IS NULL OR (to_tsvector('simple', t.memo || ' ' || t.reference) || jsonb_to_tsvector('simple', t.metadata, '["string"]')) @@ websearch_to_tsquery('simple', sqlc.narg(query)))
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR (t.created_at, t.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetRefundTotals :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount,
+       COALESCE(SUM(recipient_amount), 0)::bigint AS recipient_amount
+FROM transfers
+WHERE parent_id = $1 AND kind = 'refund';
+
+-- name: GetFeeTransfer :one
+SELECT * FROM transfers
+WHERE parent_id = $1 AND kind = 'fee'
+LIMIT 1;
+
+-- name: GetTransferUsage :one
+SELECT COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(day_start)), 0)::bigint AS daily_amount,
+       (COUNT(*) FILTER (WHERE created_at >= sqlc.arg(day_start) AND batch_id IS NULL) +
+        COUNT(DISTINCT batch_id) FILTER (WHERE created_at >= sqlc.arg(day_start)))::bigint AS daily_count,
+       COALESCE(SUM(amount), 0)::bigint AS monthly_amount,
+       (COUNT(*) FILTER (WHERE batch_id IS NULL) + COUNT(DISTINCT batch_id))::bigint AS monthly_count
+FROM transfers
+WHERE sender_id IN (
+    SELECT id FROM accounts
+    WHERE id = sqlc.arg(account_id)
+       OR (owner_id = sqlc.narg(owner_id) AND currency = sqlc.arg(currency) AND kind = 'customer')
+)
+  AND kind IN ('transfer', 'batch', 'withdrawal')
+  AND created_at >= sqlc.arg(month_start);
+
+-- name: LockTransferUsage :exec
+SELECT pg_advisory_xact_lock(hashtextextended('transfer_usage:' || sqlc.arg(currency)::text || ':' || sqlc.arg(owner_id)::bigint, 0));
+
+-- name: GetMemberSpendUsage :one
+SELECT ((
+    SELECT COALESCE(SUM(amount), 0) FROM transfers
+    WHERE sender_id = sqlc.arg(account_id) AND created_by = sqlc.arg(user_id) AND created_at >= sqlc.arg(day_start)
+) + (
+    SELECT COALESCE(SUM(CASE WHEN status = 'captured' THEN captured_amount ELSE amount END), 0) FROM holds
+    WHERE account_id = sqlc.arg(account_id) AND created_by = sqlc.arg(user_id) AND created_at >= sqlc.arg(day_start)
+      AND status IN ('active', 'captured')
+))::bigint AS amount;
\ No newline at end of file
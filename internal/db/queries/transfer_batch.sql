@@ -0,0 +1,36 @@
+-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches
+(
+    sender_id,
+    currency,
+    status,
+    line_count,
+    total_amount,
+    total_fee,
+    error
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetTransferBatch :one
+SELECT * FROM transfer_batches
+WHERE id = $1
+LIMIT 1;
+
+-- name: CreateTransferBatchLine :one
+INSERT INTO transfer_batch_lines
+(
+    batch_id,
+    line,
+    recipient_id,
+    amount,
+    transfer_id,
+    error
+)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListTransferBatchLines :many
+SELECT * FROM transfer_batch_lines
+WHERE batch_id = $1
+ORDER BY line;
\ No newline at end of file
@@ -0,0 +1,45 @@
+-- name: ListTransferLimitOverrides :many
+SELECT * FROM transfer_limits
+WHERE account_id = sqlc.arg(account_id)
+   OR (user_id = sqlc.arg(user_id) AND currency = sqlc.arg(currency))
+ORDER BY account_id NULLS FIRST;
+
+-- name: UpsertUserTransferLimits :one
+INSERT INTO transfer_limits
+(
+    user_id,
+    currency,
+    per_transfer,
+    daily_amount,
+    monthly_amount,
+    daily_count,
+    monthly_count
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id, currency) WHERE user_id IS NOT NULL DO UPDATE
+SET per_transfer = EXCLUDED.per_transfer,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    daily_count = EXCLUDED.daily_count,
+    monthly_count = EXCLUDED.monthly_count
+RETURNING *;
+
+-- name: UpsertAccountTransferLimits :one
+INSERT INTO transfer_limits
+(
+    account_id,
+    currency,
+    per_transfer,
+    daily_amount,
+    monthly_amount,
+    daily_count,
+    monthly_count
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (account_id) WHERE account_id IS NOT NULL DO UPDATE
+SET per_transfer = EXCLUDED.per_transfer,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    daily_count = EXCLUDED.daily_count,
+    monthly_count = EXCLUDED.monthly_count
+RETURNING *;
\ No newline at end of file
@@ -8,6 +8,12 @@ SELECT * FROM users
 WHERE username = $1
 LIMIT 1;
 
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE lower(email) = lower($1)
+  AND (SELECT count(*) FROM users WHERE lower(email) = lower($1)) = 1
+LIMIT 1;
+
 -- name: CreateUser :one
 INSERT INTO users (
     username,
@@ -7,27 +7,176 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const addAccountAvailableBalance = `-- name: AddAccountAvailableBalance :one
+UPDATE accounts
+SET available_balance = available_balance + $1
+WHERE id = $2
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
+`
+
+type AddAccountAvailableBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountAvailableBalance(ctx context.Context, arg AddAccountAvailableBalanceParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountAvailableBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const addAccountBalance = `-- name: AddAccountBalance :one
+UPDATE accounts
+SET balance = balance + $1,
+    available_balance = available_balance + $1
+WHERE id = $2
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
+`
+
+type AddAccountBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const claimOverdrawnAccount = `-- name: ClaimOverdrawnAccount :one
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
+WHERE kind = 'customer'
+  AND balance < 0
+  AND NOT EXISTS (
+    SELECT 1 FROM transfers
+    WHERE transfers.sender_id = accounts.id
+      AND transfers.kind = 'overdraft_fee'
+      AND transfers.created_at >= $1
+  )
+  AND id <> ALL(COALESCE($2::bigint[], '{}'))
+ORDER BY id
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED
+`
+
+type ClaimOverdrawnAccountParams struct {
+	DayStart   time.Time `json:"day_start"`
+	SkippedIds []int64   `json:"skipped_ids"`
+}
+
+func (q *Queries) ClaimOverdrawnAccount(ctx context.Context, arg ClaimOverdrawnAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, claimOverdrawnAccount, arg.DayStart, pq.Array(arg.SkippedIds))
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const clearPrimaryAccount = `-- name: ClearPrimaryAccount :exec
+UPDATE accounts
+SET is_primary = false
+WHERE owner_id = $1 AND currency = $2 AND is_primary
+`
+
+type ClearPrimaryAccountParams struct {
+	OwnerID  int64  `json:"owner_id"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) ClearPrimaryAccount(ctx context.Context, arg ClearPrimaryAccountParams) error {
+	_, err := q.db.ExecContext(ctx, clearPrimaryAccount, arg.OwnerID, arg.Currency)
+	return err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts
 (
     owner_id,
     balance,
-    currency
+    available_balance,
+    currency,
+    name,
+    purpose,
+    is_primary
 )
-VALUES ($1, $2, $3)
-RETURNING id, owner_id, balance, currency, created_at
+VALUES ($1, $2, $2, $3, $4, $5, $6)
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
 `
 
 type CreateAccountParams struct {
-	OwnerID  int64  `json:"owner_id"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	OwnerID   int64  `json:"owner_id"`
+	Balance   int64  `json:"balance"`
+	Currency  string `json:"currency"`
+	Name      string `json:"name"`
+	Purpose   string `json:"purpose"`
+	IsPrimary bool   `json:"is_primary"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.OwnerID, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.OwnerID,
+		arg.Balance,
+		arg.Currency,
+		arg.Name,
+		arg.Purpose,
+		arg.IsPrimary,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -35,12 +184,22 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
 	)
 	return i, err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner_id, balance, currency, created_at FROM accounts
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
 WHERE id = $1
 LIMIT 1
 `
@@ -54,6 +213,434 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const getOwnerAccount = `-- name: GetOwnerAccount :one
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
+WHERE owner_id = $1 AND currency = $2 AND kind = 'customer' AND status <> 'closed'
+ORDER BY is_primary DESC, id
+LIMIT 1
+`
+
+type GetOwnerAccountParams struct {
+	OwnerID  int64  `json:"owner_id"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetOwnerAccount(ctx context.Context, arg GetOwnerAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getOwnerAccount, arg.OwnerID, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const getSystemAccount = `-- name: GetSystemAccount :one
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
+WHERE kind = $1 AND currency = $2
+LIMIT 1
+`
+
+type GetSystemAccountParams struct {
+	Kind     string `json:"kind"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getSystemAccount, arg.Kind, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const listAccountsByIDs = `-- name: ListAccountsByIDs :many
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
+WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) ListAccountsByIDs(ctx context.Context, ids []int64) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AvailableBalance,
+			&i.Kind,
+			&i.OverdraftPolicy,
+			&i.OverdraftLimit,
+			&i.InterestRateBps,
+			&i.InterestAccruedTo,
+			&i.Status,
+			&i.Name,
+			&i.Purpose,
+			&i.IsPrimary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerAccountTotals = `-- name: ListOwnerAccountTotals :many
+SELECT currency,
+       COUNT(*) AS accounts,
+       SUM(balance)::bigint AS balance,
+       SUM(available_balance)::bigint AS available_balance
+FROM accounts
+WHERE id IN (
+    SELECT account_id FROM account_members
+    WHERE user_id = $1 AND status = 'active'
+  )
+  AND kind = 'customer'
+  AND ($2::varchar IS NULL OR currency = $2)
+GROUP BY currency
+ORDER BY currency
+`
+
+type ListOwnerAccountTotalsParams struct {
+	OwnerID  int64          `json:"owner_id"`
+	Currency sql.NullString `json:"currency"`
+}
+
+type ListOwnerAccountTotalsRow struct {
+	Currency         string `json:"currency"`
+	Accounts         int64  `json:"accounts"`
+	Balance          int64  `json:"balance"`
+	AvailableBalance int64  `json:"available_balance"`
+}
+
+func (q *Queries) ListOwnerAccountTotals(ctx context.Context, arg ListOwnerAccountTotalsParams) ([]ListOwnerAccountTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerAccountTotals, arg.OwnerID, arg.Currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOwnerAccountTotalsRow{}
+	for rows.Next() {
+		var i ListOwnerAccountTotalsRow
+		if err := rows.Scan(
+			&i.Currency,
+			&i.Accounts,
+			&i.Balance,
+			&i.AvailableBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerAccounts = `-- name: ListOwnerAccounts :many
+SELECT id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary FROM accounts
+WHERE id IN (
+    SELECT account_id FROM account_members
+    WHERE user_id = $1 AND status = 'active'
+  )
+  AND kind = 'customer'
+  AND ($2::varchar IS NULL OR currency = $2)
+  AND ($3::bigint IS NULL OR CASE $4::text
+    WHEN 'balance' THEN (balance, id) < ($5::bigint, $3)
+    WHEN 'currency' THEN (currency, id) > ($6::varchar, $3)
+    WHEN 'created_at' THEN (created_at, id) < ($7::timestamptz, $3)
+  END)
+ORDER BY CASE WHEN $4 = 'balance' THEN balance END DESC,
+         CASE WHEN $4 = 'currency' THEN currency END,
+         CASE WHEN $4 = 'currency' THEN id END,
+         CASE WHEN $4 = 'created_at' THEN created_at END DESC,
+         id DESC
+LIMIT $8
+`
+
+type ListOwnerAccountsParams struct {
+	OwnerID         int64          `json:"owner_id"`
+	Currency        sql.NullString `json:"currency"`
+	CursorID        sql.NullInt64  `json:"cursor_id"`
+	Sort            string         `json:"sort"`
+	CursorBalance   sql.NullInt64  `json:"cursor_balance"`
+	CursorCurrency  sql.NullString `json:"cursor_currency"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	PageSize        int32          `json:"page_size"`
+}
+
+func (q *Queries) ListOwnerAccounts(ctx context.Context, arg ListOwnerAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerAccounts,
+		arg.OwnerID,
+		arg.Currency,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorBalance,
+		arg.CursorCurrency,
+		arg.CursorCreatedAt,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AvailableBalance,
+			&i.Kind,
+			&i.OverdraftPolicy,
+			&i.OverdraftLimit,
+			&i.InterestRateBps,
+			&i.InterestAccruedTo,
+			&i.Status,
+			&i.Name,
+			&i.Purpose,
+			&i.IsPrimary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPrimaryAccount = `-- name: SetPrimaryAccount :one
+UPDATE accounts
+SET is_primary = true
+WHERE id = $1
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
+`
+
+func (q *Queries) SetPrimaryAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, setPrimaryAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const updateAccountName = `-- name: UpdateAccountName :one
+UPDATE accounts
+SET name = COALESCE($1, name),
+    purpose = COALESCE($2, purpose)
+WHERE id = $3
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
+`
+
+type UpdateAccountNameParams struct {
+	Name    sql.NullString `json:"name"`
+	Purpose sql.NullString `json:"purpose"`
+	ID      int64          `json:"id"`
+}
+
+func (q *Queries) UpdateAccountName(ctx context.Context, arg UpdateAccountNameParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountName, arg.Name, arg.Purpose, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const updateAccountOverdraft = `-- name: UpdateAccountOverdraft :one
+UPDATE accounts
+SET overdraft_policy = $2,
+    overdraft_limit = $3
+WHERE id = $1
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
+`
+
+type UpdateAccountOverdraftParams struct {
+	ID              int64  `json:"id"`
+	OverdraftPolicy string `json:"overdraft_policy"`
+	OverdraftLimit  int64  `json:"overdraft_limit"`
+}
+
+func (q *Queries) UpdateAccountOverdraft(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraft, arg.ID, arg.OverdraftPolicy, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2
+WHERE id = $1
+RETURNING id, owner_id, balance, currency, created_at, available_balance, kind, overdraft_policy, overdraft_limit, interest_rate_bps, interest_accrued_to, status, name, purpose, is_primary
+`
+
+type UpdateAccountStatusParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.ID, arg.Status)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AvailableBalance,
+		&i.Kind,
+		&i.OverdraftPolicy,
+		&i.OverdraftLimit,
+		&i.InterestRateBps,
+		&i.InterestAccruedTo,
+		&i.Status,
+		&i.Name,
+		&i.Purpose,
+		&i.IsPrimary,
 	)
 	return i, err
 }
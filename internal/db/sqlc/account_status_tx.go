@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gobank/internal/interest"
+	"time"
+)
+
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed"
+)
+
+var ErrAccountFrozen = errors.New("account is frozen")
+var ErrAccountClosed = errors.New("account is closed")
+var ErrAccountNotEmpty = errors.New("only an account without balance, holds or interest to be paid can be closed")
+var ErrInvalidAccountStatus = errors.New("account status can't change this way")
+
+// accountTransitions lists the statuses an account can move to from each status, closed is final.
+var accountTransitions = map[string][]string{
+	AccountActive: {AccountFrozen, AccountClosed},
+	AccountFrozen: {AccountActive},
+}
+
+type ChangeAccountStatusTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	// ChangedBy is the user the change is recorded for.
+	ChangedBy int64 `json:"changed_by"`
+}
+
+type ChangeAccountStatusTxResult struct {
+	Account Account             `json:"account"`
+	Change  AccountStatusChange `json:"change"`
+}
+
+// ChangeAccountStatusTx moves an account to another status and records the change.
+// Closing an account also cancels its scheduled transfers and pending payment requests and stops its interest,
+// ErrAccountNotEmpty is returned if it still holds money or would be paid interest.
+func (s *SQLStore) ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error) {
+	var result ChangeAccountStatusTxResult
+
+	err := s.execTx(ctx, []int64{arg.AccountID}, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if !canChangeAccountStatus(account.Status, arg.Status) {
+			return fmt.Errorf("%w: from %s to %s", ErrInvalidAccountStatus, account.Status, arg.Status)
+		}
+
+		if arg.Status == AccountClosed {
+			if err := closeAccount(ctx, q, account); err != nil {
+				return err
+			}
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     account.ID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Change, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+			AccountID:  account.ID,
+			FromStatus: account.Status,
+			ToStatus:   arg.Status,
+			Reason:     arg.Reason,
+			ChangedBy:  arg.ChangedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+func canChangeAccountStatus(from string, to string) bool {
+	for _, status := range accountTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// closeAccount checks that nothing is left in the account and stops everything that would move money in or out of it.
+func closeAccount(ctx context.Context, q *Queries, account Account) error {
+	if account.Balance != 0 || account.AvailableBalance != 0 {
+		return ErrAccountNotEmpty
+	}
+
+	today := interest.Day(time.Now())
+
+	// accruals of a zero balance are zero too, only the days before the balance was emptied matter
+	accrued, err := q.GetUncapitalizedInterest(ctx, GetUncapitalizedInterestParams{
+		AccountID: account.ID,
+		Before:    today.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return err
+	}
+
+	amount, err := interest.Parse(accrued.Amount)
+	if err != nil {
+		return err
+	}
+
+	if interest.Round(amount) != 0 {
+		return ErrAccountNotEmpty
+	}
+
+	err = q.CapitalizeInterestAccruals(ctx, CapitalizeInterestAccrualsParams{
+		AccountID: account.ID,
+		Before:    today.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.SetAccountInterestRate(ctx, SetAccountInterestRateParams{
+		ID:              account.ID,
+		InterestRateBps: 0,
+		Today:           sql.NullTime{Time: today, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := q.CancelAccountScheduledTransfers(ctx, account.ID); err != nil {
+		return err
+	}
+
+	return q.CancelAccountPaymentRequests(ctx, account.ID)
+}
+
+// checkAccountStatus returns the error of a transfer the status of its accounts doesn't allow.
+// Closed accounts take part in no transfer. Frozen accounts can still be charged fees by the bank,
+// and receive refunds and interest, other transfers only if FrozenAccountsCanReceive is set.
+func (s *SQLStore) checkAccountStatus(sender Account, recipient Account, kind string) error {
+	if sender.Status == AccountClosed || recipient.Status == AccountClosed {
+		return ErrAccountClosed
+	}
+
+	if sender.Status == AccountFrozen && kind != TransferKindOverdraftFee && kind != TransferKindFee {
+		return ErrAccountFrozen
+	}
+
+	if recipient.Status == AccountFrozen && !s.config.FrozenAccountsCanReceive &&
+		kind != TransferKindRefund && kind != TransferKindInterest {
+		return ErrAccountFrozen
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+)
+
+func changeAccountStatus(t *testing.T, store Store, account Account, status string) ChangeAccountStatusTxResult {
+	result, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    status,
+		Reason:    "test",
+		ChangedBy: account.OwnerID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, status, result.Account.Status)
+	require.Equal(t, account.ID, result.Change.AccountID)
+	require.Equal(t, status, result.Change.ToStatus)
+
+	return result
+}
+
+func TestChangeAccountStatusTxFreeze(t *testing.T) {
+	config, err := util.LoadConfig("../../..")
+	require.NoError(t, err)
+	config.FrozenAccountsCanReceive = false
+	store := NewSQLStore(testDB, config)
+
+	sender, recipient := createRandomAccountPair(t)
+	result := changeAccountStatus(t, store, sender, AccountFrozen)
+	require.Equal(t, AccountActive, result.Change.FromStatus)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    recipient.ID,
+		RecipientID: sender.ID,
+		Amount:      10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// a frozen account can't be closed before it is unfrozen
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: sender.ID,
+		Status:    AccountClosed,
+		ChangedBy: sender.OwnerID,
+	})
+	require.ErrorIs(t, err, ErrInvalidAccountStatus)
+
+	changeAccountStatus(t, store, sender, AccountActive)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      10,
+	})
+	require.NoError(t, err)
+}
+
+func TestChangeAccountStatusTxFrozenCanReceive(t *testing.T) {
+	config, err := util.LoadConfig("../../..")
+	require.NoError(t, err)
+	config.FrozenAccountsCanReceive = true
+	store := NewSQLStore(testDB, config)
+
+	sender, recipient := createRandomAccountPair(t)
+	changeAccountStatus(t, store, recipient, AccountFrozen)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, recipient.Balance+10, result.RecipientAccount.Balance)
+}
+
+func TestChangeAccountStatusTxClose(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+
+	_, err := testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: sender.ID,
+		Status:    AccountClosed,
+		ChangedBy: sender.OwnerID,
+	})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      sender.Balance,
+	})
+	require.NoError(t, err)
+
+	result := changeAccountStatus(t, testStore, sender, AccountClosed)
+	require.Zero(t, result.Account.InterestRateBps)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    recipient.ID,
+		RecipientID: sender.ID,
+		Amount:      10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// closed is final
+	_, err = testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: sender.ID,
+		Status:    AccountActive,
+		ChangedBy: sender.OwnerID,
+	})
+	require.ErrorIs(t, err, ErrInvalidAccountStatus)
+
+	// the currency is free again for a new account of the owner
+	owner, err := testQueries.GetUser(context.Background(), sender.OwnerID)
+	require.NoError(t, err)
+	createRandomAccountForUser(t, owner, sender.Currency)
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"github.com/stretchr/testify/require"
 	"gobank/internal/util"
 	"testing"
@@ -33,6 +34,18 @@ func createRandomAccount(t *testing.T) Account {
 	return account
 }
 
+// createRandomAccountForUser creates an account with enough balance for the transfers made in tests.
+func createRandomAccountForUser(t *testing.T, user User, currency string) Account {
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		OwnerID:  user.ID,
+		Balance:  util.RandomInt(1000, 2000),
+		Currency: currency,
+		Name:     currency,
+	})
+	require.NoError(t, err)
+	return account
+}
+
 func TestCreateAccount(t *testing.T) {
 	createRandomAccount(t)
 }
@@ -49,3 +62,76 @@ func TestGetAccount(t *testing.T) {
 	require.Equal(t, account1.Currency, account2.Currency)
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
+
+func TestGetOwnerAccount(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.GetOwnerAccount(context.Background(), GetOwnerAccountParams{
+		OwnerID:  account1.OwnerID,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+
+	for _, currency := range []string{util.EUR, util.USD, util.RUB} {
+		if currency == account1.Currency {
+			continue
+		}
+		_, err = testQueries.GetOwnerAccount(context.Background(), GetOwnerAccountParams{
+			OwnerID:  account1.OwnerID,
+			Currency: currency,
+		})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	}
+}
+
+func TestListOwnerAccounts(t *testing.T) {
+	user := createRandomUser(t)
+	var total int64
+	for _, currency := range []string{util.USD, util.EUR, util.RUB} {
+		total += createPocket(t, user, currency, currency, true).Balance
+	}
+
+	page1, err := testQueries.ListOwnerAccounts(context.Background(), ListOwnerAccountsParams{
+		OwnerID:  user.ID,
+		Sort:     "currency",
+		PageSize: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	require.Equal(t, util.EUR, page1[0].Currency)
+	require.Equal(t, util.RUB, page1[1].Currency)
+
+	page2, err := testQueries.ListOwnerAccounts(context.Background(), ListOwnerAccountsParams{
+		OwnerID:        user.ID,
+		Sort:           "currency",
+		CursorID:       sql.NullInt64{Int64: page1[1].ID, Valid: true},
+		CursorCurrency: sql.NullString{String: page1[1].Currency, Valid: true},
+		PageSize:       2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Equal(t, util.USD, page2[0].Currency)
+
+	byBalance, err := testQueries.ListOwnerAccounts(context.Background(), ListOwnerAccountsParams{
+		OwnerID:  user.ID,
+		Sort:     "balance",
+		PageSize: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, byBalance, 3)
+	require.GreaterOrEqual(t, byBalance[0].Balance, byBalance[1].Balance)
+	require.GreaterOrEqual(t, byBalance[1].Balance, byBalance[2].Balance)
+
+	totals, err := testQueries.ListOwnerAccountTotals(context.Background(), ListOwnerAccountTotalsParams{
+		OwnerID: user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, totals, 3)
+
+	var sum int64
+	for _, row := range totals {
+		require.Equal(t, int64(1), row.Accounts)
+		sum += row.Balance
+	}
+	require.Equal(t, total, sum)
+}
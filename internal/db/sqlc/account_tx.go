@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"time"
+)
+
+const (
+	AccountMemberOwner   = "owner"
+	AccountMemberSpender = "spender"
+	AccountMemberViewer  = "viewer"
+)
+
+const (
+	AccountMemberInvited = "invited"
+	AccountMemberActive  = "active"
+)
+
+// ErrAccountNameTaken is returned when the owner already has an open account with the name, names are case insensitive.
+var ErrAccountNameTaken = errors.New("an account with this name already exists")
+
+// ownerNameKey is the unique index on the names of the open accounts of an owner.
+const ownerNameKey = "owner_name_key"
+
+// CreateAccountTx opens an account with its owner as first member. The first open account of an owner
+// in a currency is always primary, making a new account primary takes the flag from the previous one.
+func (s *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var account Account
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		if err := takePrimary(ctx, q, arg.OwnerID, arg.Currency, &arg.IsPrimary); err != nil {
+			return err
+		}
+
+		var err error
+		account, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAccountMember(ctx, CreateAccountMemberParams{
+			AccountID: account.ID,
+			UserID:    account.OwnerID,
+			Role:      AccountMemberOwner,
+			Status:    AccountMemberActive,
+		})
+		return err
+	})
+
+	return account, nameTakenError(err)
+}
+
+type UpdateAccountTxParams struct {
+	ID      int64          `json:"id"`
+	Name    sql.NullString `json:"name"`
+	Purpose sql.NullString `json:"purpose"`
+	// Primary makes the account the primary one of its currency, the flag can't be removed this way.
+	Primary bool `json:"primary"`
+}
+
+// UpdateAccountTx renames an account, changes its purpose or makes it the primary account of its currency.
+func (s *SQLStore) UpdateAccountTx(ctx context.Context, arg UpdateAccountTxParams) (Account, error) {
+	var account Account
+
+	err := s.execTx(ctx, []int64{arg.ID}, func(q *Queries) error {
+		var err error
+		account, err = q.UpdateAccountName(ctx, UpdateAccountNameParams{
+			ID:      arg.ID,
+			Name:    arg.Name,
+			Purpose: arg.Purpose,
+		})
+		if err != nil || !arg.Primary || account.IsPrimary {
+			return err
+		}
+
+		if account.Status == AccountClosed {
+			return ErrAccountClosed
+		}
+
+		err = q.ClearPrimaryAccount(ctx, ClearPrimaryAccountParams{
+			OwnerID:  account.OwnerID,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		account, err = q.SetPrimaryAccount(ctx, account.ID)
+		return err
+	})
+
+	return account, nameTakenError(err)
+}
+
+// RemoveAccountMemberTx removes a member or an invitation from an account. The scheduled transfers the member
+// created from the account are cancelled and their active holds on it voided, so nothing they set up keeps spending.
+func (s *SQLStore) RemoveAccountMemberTx(ctx context.Context, member AccountMember) error {
+	return s.execTx(ctx, nil, func(q *Queries) error {
+		if err := q.DeleteAccountMember(ctx, member.ID); err != nil {
+			return err
+		}
+
+		createdBy := sql.NullInt64{Int64: member.UserID, Valid: true}
+		err := q.CancelMemberScheduledTransfers(ctx, CancelMemberScheduledTransfersParams{
+			SenderID:  member.AccountID,
+			CreatedBy: createdBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the holds are locked before the account, like VoidHoldTx does
+		holds, err := q.ListMemberActiveHolds(ctx, ListMemberActiveHoldsParams{
+			AccountID: member.AccountID,
+			CreatedBy: createdBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, hold := range holds {
+			// expired holds are released by the expiry job
+			if !hold.ExpiresAt.After(now) {
+				continue
+			}
+
+			if _, err := releaseHold(ctx, q, hold, HoldVoided); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// takePrimary clears the primary flag of the owner's accounts in the currency if the new account takes it,
+// or sets it when the owner has no open account in the currency yet.
+func takePrimary(ctx context.Context, q *Queries, ownerID int64, currency string, primary *bool) error {
+	if *primary {
+		return q.ClearPrimaryAccount(ctx, ClearPrimaryAccountParams{
+			OwnerID:  ownerID,
+			Currency: currency,
+		})
+	}
+
+	_, err := q.GetOwnerAccount(ctx, GetOwnerAccountParams{
+		OwnerID:  ownerID,
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		*primary = true
+		return nil
+	}
+	return err
+}
+
+func nameTakenError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == ownerNameKey {
+		return ErrAccountNameTaken
+	}
+	return err
+}
+
+// TransferKindBetween returns the kind of a plain transfer between the accounts,
+// TransferKindMove between accounts of one owner in one currency.
+func TransferKindBetween(sender Account, recipient Account) string {
+	if sender.OwnerID == recipient.OwnerID && sender.Currency == recipient.Currency {
+		return TransferKindMove
+	}
+	return TransferKindTransfer
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func createPocket(t *testing.T, user User, currency string, name string, primary bool) Account {
+	account, err := testStore.CreateAccountTx(context.Background(), CreateAccountParams{
+		OwnerID:   user.ID,
+		Balance:   util.RandomInt(1000, 2000),
+		Currency:  currency,
+		Name:      name,
+		IsPrimary: primary,
+	})
+	require.NoError(t, err)
+	require.Equal(t, name, account.Name)
+
+	return account
+}
+
+func TestCreateAccountTx(t *testing.T) {
+	user := createRandomUser(t)
+
+	main := createPocket(t, user, util.EUR, "Main", false)
+	require.True(t, main.IsPrimary)
+
+	savings := createPocket(t, user, util.EUR, "Savings", false)
+	require.False(t, savings.IsPrimary)
+
+	_, err := testStore.CreateAccountTx(context.Background(), CreateAccountParams{
+		OwnerID:  user.ID,
+		Currency: util.USD,
+		Name:     "savings",
+	})
+	require.ErrorIs(t, err, ErrAccountNameTaken)
+
+	travel := createPocket(t, user, util.EUR, "Travel", true)
+	require.True(t, travel.IsPrimary)
+
+	main, err = testQueries.GetAccount(context.Background(), main.ID)
+	require.NoError(t, err)
+	require.False(t, main.IsPrimary)
+
+	primary, err := testQueries.GetOwnerAccount(context.Background(), GetOwnerAccountParams{
+		OwnerID:  user.ID,
+		Currency: util.EUR,
+	})
+	require.NoError(t, err)
+	require.Equal(t, travel.ID, primary.ID)
+}
+
+func TestUpdateAccountTx(t *testing.T) {
+	user := createRandomUser(t)
+	main := createPocket(t, user, util.USD, "Main", false)
+	savings := createPocket(t, user, util.USD, "Savings", false)
+
+	_, err := testStore.UpdateAccountTx(context.Background(), UpdateAccountTxParams{
+		ID:   savings.ID,
+		Name: sql.NullString{String: "MAIN", Valid: true},
+	})
+	require.ErrorIs(t, err, ErrAccountNameTaken)
+
+	account, err := testStore.UpdateAccountTx(context.Background(), UpdateAccountTxParams{
+		ID:      savings.ID,
+		Purpose: sql.NullString{String: "holidays", Valid: true},
+		Primary: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Savings", account.Name)
+	require.Equal(t, "holidays", account.Purpose)
+	require.True(t, account.IsPrimary)
+
+	main, err = testQueries.GetAccount(context.Background(), main.ID)
+	require.NoError(t, err)
+	require.False(t, main.IsPrimary)
+}
+
+func TestTransferTxMove(t *testing.T) {
+	store := newFeeTestStore(t)
+	user := createRandomUser(t)
+	main := createPocket(t, user, util.RUB, "Main", false)
+	savings := createPocket(t, user, util.RUB, "Savings", false)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    main.ID,
+		RecipientID: savings.ID,
+		Amount:      100,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferKindMove, result.Transfer.Kind)
+	require.Nil(t, result.FeeTransfer)
+	require.Equal(t, main.Balance-100, result.SenderAccount.Balance)
+	require.Equal(t, savings.Balance+100, result.RecipientAccount.Balance)
+}
+
+func TestAccountMembers(t *testing.T) {
+	holder := createRandomUser(t)
+	account := createPocket(t, holder, util.EUR, "Household", false)
+
+	members, err := testQueries.ListAccountMembers(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, holder.ID, members[0].UserID)
+	require.Equal(t, AccountMemberOwner, members[0].Role)
+	require.Equal(t, AccountMemberActive, members[0].Status)
+	require.True(t, members[0].AcceptedAt.Valid)
+
+	user := createRandomUser(t)
+	invitation, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID:  account.ID,
+		UserID:     user.ID,
+		Role:       AccountMemberSpender,
+		SpendLimit: sql.NullInt64{Int64: 100, Valid: true},
+		Status:     AccountMemberInvited,
+		InvitedBy:  sql.NullInt64{Int64: holder.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.False(t, invitation.AcceptedAt.Valid)
+
+	listed := func() []Account {
+		accounts, err := testQueries.ListOwnerAccounts(context.Background(), ListOwnerAccountsParams{
+			OwnerID:  user.ID,
+			Sort:     "created_at",
+			PageSize: 10,
+		})
+		require.NoError(t, err)
+		return accounts
+	}
+
+	// invitations don't give access until they are accepted
+	require.Empty(t, listed())
+
+	invitations, err := testQueries.ListUserInvitations(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+
+	member, err := testQueries.AcceptAccountMember(context.Background(), invitation.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountMemberActive, member.Status)
+	require.True(t, member.AcceptedAt.Valid)
+
+	_, err = testQueries.AcceptAccountMember(context.Background(), invitation.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	accounts := listed()
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+
+	require.NoError(t, testQueries.DeleteAccountMember(context.Background(), member.ID))
+	require.Empty(t, listed())
+}
+
+func TestRemoveAccountMemberTx(t *testing.T) {
+	holder := createRandomUser(t)
+	account := createPocket(t, holder, util.EUR, "Household", false)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), util.EUR)
+
+	user := createRandomUser(t)
+	member, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID: account.ID,
+		UserID:    user.ID,
+		Role:      AccountMemberSpender,
+		Status:    AccountMemberActive,
+		InvitedBy: sql.NullInt64{Int64: holder.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	schedule := func(createdBy int64) ScheduledTransfer {
+		scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+			SenderID:    account.ID,
+			RecipientID: recipient.ID,
+			Amount:      10,
+			Recurrence:  "monthly",
+			StartAt:     time.Now().Add(time.Hour),
+			NextRunAt:   sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+			CreatedBy:   sql.NullInt64{Int64: createdBy, Valid: true},
+		})
+		require.NoError(t, err)
+		return scheduled
+	}
+
+	hold := func(createdBy int64) Hold {
+		result, err := testStore.AuthorizeHoldTx(context.Background(), CreateHoldParams{
+			AccountID:   account.ID,
+			RecipientID: recipient.ID,
+			Amount:      10,
+			ExpiresAt:   time.Now().Add(time.Hour),
+			CreatedBy:   sql.NullInt64{Int64: createdBy, Valid: true},
+		})
+		require.NoError(t, err)
+		return result.Hold
+	}
+
+	memberSchedule, holderSchedule := schedule(user.ID), schedule(holder.ID)
+	memberHold, holderHold := hold(user.ID), hold(holder.ID)
+
+	require.NoError(t, testStore.RemoveAccountMemberTx(context.Background(), member))
+
+	_, err = testQueries.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: account.ID,
+		UserID:    user.ID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// only what the removed member set up is cancelled
+	scheduled, err := testQueries.GetScheduledTransfer(context.Background(), memberSchedule.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferCancelled, scheduled.Status)
+
+	scheduled, err = testQueries.GetScheduledTransfer(context.Background(), holderSchedule.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferActive, scheduled.Status)
+
+	voided, err := testQueries.GetHold(context.Background(), memberHold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldVoided, voided.Status)
+
+	active, err := testQueries.GetHold(context.Background(), holderHold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, active.Status)
+
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.AvailableBalance-10, updated.AvailableBalance)
+}
@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	TransferBatchCompleted = "completed"
+	TransferBatchRejected  = "rejected"
+)
+
+var ErrBatchRejected = errors.New("transfer batch was rejected, none of its lines was executed")
+
+type BatchLine struct {
+	RecipientID int64 `json:"recipient_id"`
+	Amount      int64 `json:"amount"`
+}
+
+type BatchTransferTxParams struct {
+	SenderID int64       `json:"sender_id"`
+	Currency string      `json:"currency"`
+	Lines    []BatchLine `json:"lines"`
+	// CreatedBy is the account member who sends the batch, the whole batch counts against their spend limit.
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+type BatchTransferTxResult struct {
+	Batch TransferBatch       `json:"batch"`
+	Lines []TransferBatchLine `json:"lines"`
+	// SenderAccount is only set when the batch is completed.
+	SenderAccount Account `json:"sender_account"`
+}
+
+// BatchTransferTx executes every line of a batch from one sender account, all of them or none.
+// The lines are validated before anything is written and the batch is rejected if the sender can't afford
+// all of them with their fees, they exceed its limits or it can't send money. A batch counts as one transfer against the count limits.
+// A rejected batch is still recorded, with the reason and the errors of its lines, and an error wrapping
+// ErrBatchRejected is returned with it.
+func (s *SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+
+	if len(arg.Lines) == 0 {
+		return result, errors.New("a batch needs at least one line")
+	}
+
+	lineErrors, err := s.validateBatch(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+	if len(lineErrors) > 0 {
+		return s.rejectBatch(ctx, arg, errors.New("some lines are invalid"), lineErrors)
+	}
+
+	lockIDs := []int64{arg.SenderID}
+	for _, line := range arg.Lines {
+		lockIDs = append(lockIDs, line.RecipientID)
+	}
+
+	err = s.execTx(ctx, lockIDs, func(q *Queries) error {
+		sender, err := q.GetAccount(ctx, arg.SenderID)
+		if err != nil {
+			return err
+		}
+
+		switch sender.Status {
+		case AccountFrozen:
+			return ErrAccountFrozen
+		case AccountClosed:
+			return ErrAccountClosed
+		}
+
+		var total, totalFee int64
+		for _, line := range arg.Lines {
+			total += line.Amount
+			totalFee += s.PreviewTransferFees(sender, TransferTxParams{Amount: line.Amount, Kind: TransferKindBatch}).Total
+		}
+
+		if !sender.canDebit(total + totalFee) {
+			return ErrInsufficientFunds
+		}
+
+		if err := s.checkBatchLimits(ctx, q, sender, arg.Lines, total); err != nil {
+			return err
+		}
+
+		if err := checkSpendLimit(ctx, q, sender, arg.CreatedBy, total+totalFee, time.Now()); err != nil {
+			return err
+		}
+
+		result.Batch, err = q.CreateTransferBatch(ctx, CreateTransferBatchParams{
+			SenderID:    arg.SenderID,
+			Currency:    arg.Currency,
+			Status:      TransferBatchCompleted,
+			LineCount:   int64(len(arg.Lines)),
+			TotalAmount: total,
+			TotalFee:    totalFee,
+		})
+		if err != nil {
+			return err
+		}
+
+		batchID := sql.NullInt64{Int64: result.Batch.ID, Valid: true}
+		result.Lines = make([]TransferBatchLine, 0, len(arg.Lines))
+
+		for i, line := range arg.Lines {
+			transfer, err := s.transfer(ctx, q, TransferTxParams{
+				SenderID:    arg.SenderID,
+				RecipientID: line.RecipientID,
+				Amount:      line.Amount,
+				Kind:        TransferKindBatch,
+				BatchID:     batchID,
+				CreatedBy:   arg.CreatedBy,
+			})
+			if err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+			batchLine, err := q.CreateTransferBatchLine(ctx, CreateTransferBatchLineParams{
+				BatchID:     result.Batch.ID,
+				Line:        int64(i + 1),
+				RecipientID: line.RecipientID,
+				Amount:      line.Amount,
+				TransferID:  sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Lines = append(result.Lines, batchLine)
+			result.SenderAccount = transfer.SenderAccount
+		}
+
+		return nil
+	})
+	if errors.Is(err, ErrInsufficientFunds) || errors.Is(err, ErrLimitExceeded) ||
+		errors.Is(err, ErrAccountFrozen) || errors.Is(err, ErrAccountClosed) {
+		return s.rejectBatch(ctx, arg, err, nil)
+	}
+
+	return result, err
+}
+
+// validateBatch returns the errors of the lines by index, the sender is expected to exist.
+func (s *SQLStore) validateBatch(ctx context.Context, arg BatchTransferTxParams) (map[int]string, error) {
+	ids := make([]int64, 0, len(arg.Lines))
+	for _, line := range arg.Lines {
+		ids = append(ids, line.RecipientID)
+	}
+
+	accounts, err := s.ListAccountsByIDs(ctx, sortedUniqueIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]Account, len(accounts))
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	lineErrors := make(map[int]string)
+	for i, line := range arg.Lines {
+		recipient, ok := byID[line.RecipientID]
+
+		switch {
+		case line.Amount <= 0:
+			lineErrors[i] = "amount must be positive"
+		case line.RecipientID == arg.SenderID:
+			lineErrors[i] = "recipient is the sender account"
+		case !ok:
+			lineErrors[i] = "recipient account doesn't exist"
+		case recipient.Currency != arg.Currency:
+			lineErrors[i] = fmt.Sprintf("recipient account currency is %s", recipient.Currency)
+		case recipient.Status == AccountClosed:
+			lineErrors[i] = "recipient account is closed"
+		case recipient.Status == AccountFrozen && !s.config.FrozenAccountsCanReceive:
+			lineErrors[i] = "recipient account is frozen"
+		}
+	}
+
+	return lineErrors, nil
+}
+
+// checkBatchLimits checks every line against the per transfer limit and the batch total against the others.
+func (s *SQLStore) checkBatchLimits(ctx context.Context, q *Queries, sender Account, lines []BatchLine, total int64) error {
+	if err := lockUsage(ctx, q, sender); err != nil {
+		return err
+	}
+
+	limits, err := s.accountLimits(ctx, q, sender, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i, line := range lines {
+		if limits.Limits.PerTransfer > 0 && line.Amount > limits.Limits.PerTransfer {
+			return fmt.Errorf("line %d: %w: at most %d per transfer", i+1, ErrLimitExceeded, limits.Limits.PerTransfer)
+		}
+	}
+
+	batchLimits := limits.Limits
+	batchLimits.PerTransfer = 0
+	return checkLimits(batchLimits, limits.Usage, total)
+}
+
+// rejectBatch records a batch that wasn't executed.
+func (s *SQLStore) rejectBatch(ctx context.Context, arg BatchTransferTxParams, reason error, lineErrors map[int]string) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+
+	var total int64
+	for _, line := range arg.Lines {
+		total += line.Amount
+	}
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+		result.Batch, err = q.CreateTransferBatch(ctx, CreateTransferBatchParams{
+			SenderID:    arg.SenderID,
+			Currency:    arg.Currency,
+			Status:      TransferBatchRejected,
+			LineCount:   int64(len(arg.Lines)),
+			TotalAmount: total,
+			Error:       sql.NullString{String: reason.Error(), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Lines = make([]TransferBatchLine, 0, len(arg.Lines))
+		for i, line := range arg.Lines {
+			lineError, ok := lineErrors[i]
+
+			batchLine, err := q.CreateTransferBatchLine(ctx, CreateTransferBatchLineParams{
+				BatchID:     result.Batch.ID,
+				Line:        int64(i + 1),
+				RecipientID: line.RecipientID,
+				Amount:      line.Amount,
+				Error:       sql.NullString{String: lineError, Valid: ok},
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Lines = append(result.Lines, batchLine)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	return result, fmt.Errorf("%w: %w", ErrBatchRejected, reason)
+}
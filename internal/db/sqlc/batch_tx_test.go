@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	currency := util.RandomCurrency()
+	sender := createRandomAccountForUser(t, createRandomUser(t), currency)
+
+	n := 5
+	arg := BatchTransferTxParams{SenderID: sender.ID, Currency: currency}
+	for i := 0; i < n; i++ {
+		recipient := createRandomAccountForUser(t, createRandomUser(t), currency)
+		arg.Lines = append(arg.Lines, BatchLine{RecipientID: recipient.ID, Amount: int64(i + 1)})
+	}
+
+	result, err := testStore.BatchTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, TransferBatchCompleted, result.Batch.Status)
+	require.Equal(t, int64(n), result.Batch.LineCount)
+	require.Equal(t, int64(15), result.Batch.TotalAmount)
+	require.Equal(t, sender.Balance-15, result.SenderAccount.Balance)
+	require.Len(t, result.Lines, n)
+
+	for i, line := range result.Lines {
+		require.Equal(t, int64(i+1), line.Line)
+		require.False(t, line.Error.Valid)
+
+		transfer, err := testQueries.GetTransfer(context.Background(), line.TransferID.Int64)
+		require.NoError(t, err)
+		require.Equal(t, TransferKindBatch, transfer.Kind)
+		require.Equal(t, result.Batch.ID, transfer.BatchID.Int64)
+		require.Equal(t, arg.Lines[i].RecipientID, transfer.RecipientID)
+	}
+
+	// the whole batch counts as one transfer
+	limits, err := testStore.AccountLimitsTx(context.Background(), sender)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), limits.Usage.DailyCount)
+	require.Equal(t, int64(15), limits.Usage.DailyAmount)
+}
+
+func TestBatchTransferTxRejected(t *testing.T) {
+	currency := util.RandomCurrency()
+	sender := createRandomAccountForUser(t, createRandomUser(t), currency)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), currency)
+	other := createRandomAccountForUser(t, createRandomUser(t), otherCurrency(currency))
+
+	result, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		SenderID: sender.ID,
+		Currency: currency,
+		Lines: []BatchLine{
+			{RecipientID: recipient.ID, Amount: 1},
+			{RecipientID: other.ID, Amount: 1},
+			{RecipientID: sender.ID, Amount: 1},
+		},
+	})
+	require.ErrorIs(t, err, ErrBatchRejected)
+	require.Equal(t, TransferBatchRejected, result.Batch.Status)
+	require.Len(t, result.Lines, 3)
+	require.False(t, result.Lines[0].Error.Valid)
+	require.True(t, result.Lines[1].Error.Valid)
+	require.True(t, result.Lines[2].Error.Valid)
+
+	// the sender can afford every line but not all of them
+	result, err = testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		SenderID: sender.ID,
+		Currency: currency,
+		Lines: []BatchLine{
+			{RecipientID: recipient.ID, Amount: sender.Balance},
+			{RecipientID: recipient.ID, Amount: 1},
+		},
+	})
+	require.ErrorIs(t, err, ErrBatchRejected)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	require.Equal(t, TransferBatchRejected, result.Batch.Status)
+
+	unchanged, err := testStore.GetAccount(context.Background(), sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, sender.Balance, unchanged.Balance)
+
+	lines, err := testQueries.ListTransferBatchLines(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		require.False(t, line.TransferID.Valid)
+	}
+}
+
+func otherCurrency(currency string) string {
+	if currency == util.EUR {
+		return util.USD
+	}
+	return util.EUR
+}
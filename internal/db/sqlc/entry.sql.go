@@ -7,10 +7,63 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const createEntry = `-- name: CreateEntry :one
+INSERT INTO entries
+(
+    account_id,
+    amount,
+    transfer_id
+)
+VALUES ($1, $2, $3)
+RETURNING id, account_id, amount, created_at, transfer_id
+`
+
+type CreateEntryParams struct {
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.TransferID,
+	)
+	return i, err
+}
+
+const getAccountBalanceAt = `-- name: GetAccountBalanceAt :one
+SELECT (a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= $1
+), 0))::bigint AS balance
+FROM accounts a
+WHERE a.id = $2
+`
+
+type GetAccountBalanceAtParams struct {
+	At        time.Time `json:"at"`
+	AccountID int64     `json:"account_id"`
+}
+
+func (q *Queries) GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAccountBalanceAt, arg.At, arg.AccountID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE id = $1
 LIMIT 1
 `
@@ -23,6 +76,190 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+	)
+	return i, err
+}
+
+const getStatementTotals = `-- name: GetStatementTotals :one
+SELECT COUNT(*) FILTER (WHERE amount > 0) AS credit_count,
+       COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0)::bigint AS total_in,
+       COUNT(*) FILTER (WHERE amount < 0) AS debit_count,
+       COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0)::bigint AS total_out
+FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+`
+
+type GetStatementTotalsParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+type GetStatementTotalsRow struct {
+	CreditCount int64 `json:"credit_count"`
+	TotalIn     int64 `json:"total_in"`
+	DebitCount  int64 `json:"debit_count"`
+	TotalOut    int64 `json:"total_out"`
+}
+
+func (q *Queries) GetStatementTotals(ctx context.Context, arg GetStatementTotalsParams) (GetStatementTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getStatementTotals, arg.AccountID, arg.FromTime, arg.ToTime)
+	var i GetStatementTotalsRow
+	err := row.Scan(
+		&i.CreditCount,
+		&i.TotalIn,
+		&i.DebitCount,
+		&i.TotalOut,
 	)
 	return i, err
 }
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT e.id,
+       e.amount,
+       e.transfer_id,
+       e.created_at,
+       COALESCE(CASE WHEN t.sender_id = e.account_id THEN t.recipient_id ELSE t.sender_id END, 0)::bigint AS counterparty_id,
+       COALESCE(t.memo, '')::varchar AS memo,
+       COALESCE(t.reference, '')::varchar AS reference,
+       ($1::bigint + SUM(e.amount) OVER (ORDER BY e.created_at, e.id))::bigint AS running_balance
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = $2
+  AND e.created_at >= $3
+  AND e.created_at < $4
+ORDER BY e.created_at, e.id
+`
+
+type ListStatementEntriesParams struct {
+	OpeningBalance int64     `json:"opening_balance"`
+	AccountID      int64     `json:"account_id"`
+	FromTime       time.Time `json:"from_time"`
+	ToTime         time.Time `json:"to_time"`
+}
+
+type ListStatementEntriesRow struct {
+	ID             int64         `json:"id"`
+	Amount         int64         `json:"amount"`
+	TransferID     sql.NullInt64 `json:"transfer_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	CounterpartyID int64         `json:"counterparty_id"`
+	Memo           string        `json:"memo"`
+	Reference      string        `json:"reference"`
+	RunningBalance int64         `json:"running_balance"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries,
+		arg.OpeningBalance,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.TransferID,
+			&i.CreatedAt,
+			&i.CounterpartyID,
+			&i.Memo,
+			&i.Reference,
+			&i.RunningBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntriesPage = `-- name: ListStatementEntriesPage :many
+SELECT e.id,
+       e.amount,
+       e.transfer_id,
+       e.created_at,
+       COALESCE(CASE WHEN t.sender_id = e.account_id THEN t.recipient_id ELSE t.sender_id END, 0)::bigint AS counterparty_id,
+       COALESCE(t.memo, '')::varchar AS memo,
+       COALESCE(t.reference, '')::varchar AS reference
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = $1
+  AND e.created_at >= $2
+  AND e.created_at < $3
+  AND (e.created_at, e.id) > ($4::timestamptz, $5::bigint)
+ORDER BY e.created_at, e.id
+LIMIT $6
+`
+
+type ListStatementEntriesPageParams struct {
+	AccountID      int64     `json:"account_id"`
+	FromTime       time.Time `json:"from_time"`
+	ToTime         time.Time `json:"to_time"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	PageSize       int32     `json:"page_size"`
+}
+
+type ListStatementEntriesPageRow struct {
+	ID             int64         `json:"id"`
+	Amount         int64         `json:"amount"`
+	TransferID     sql.NullInt64 `json:"transfer_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	CounterpartyID int64         `json:"counterparty_id"`
+	Memo           string        `json:"memo"`
+	Reference      string        `json:"reference"`
+}
+
+func (q *Queries) ListStatementEntriesPage(ctx context.Context, arg ListStatementEntriesPageParams) ([]ListStatementEntriesPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntriesPage,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesPageRow{}
+	for rows.Next() {
+		var i ListStatementEntriesPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.TransferID,
+			&i.CreatedAt,
+			&i.CounterpartyID,
+			&i.Memo,
+			&i.Reference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func createRandomExchangeRate(t *testing.T, base string, quote string, effectiveAt time.Time) ExchangeRate {
+	arg := UpsertExchangeRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          "1.2500000000",
+		Source:        util.RandomString(6),
+		EffectiveAt:   effectiveAt,
+	}
+
+	rate, err := testQueries.UpsertExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.NotZero(t, rate.ID)
+	require.Equal(t, arg.BaseCurrency, rate.BaseCurrency)
+	require.Equal(t, arg.QuoteCurrency, rate.QuoteCurrency)
+	require.Equal(t, arg.Rate, rate.Rate)
+	require.Equal(t, arg.Source, rate.Source)
+	require.WithinDuration(t, arg.EffectiveAt, rate.EffectiveAt, time.Second)
+
+	return rate
+}
+
+func TestListEffectiveExchangeRates(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	old := createRandomExchangeRate(t, util.EUR, util.USD, now.Add(-2*time.Hour))
+	current := createRandomExchangeRate(t, util.EUR, util.USD, now.Add(-time.Hour))
+	createRandomExchangeRate(t, util.EUR, util.USD, now.Add(time.Hour))
+
+	rates, err := testQueries.ListEffectiveExchangeRates(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, current.ID, findExchangeRate(t, rates, util.EUR, util.USD).ID)
+
+	rates, err = testQueries.ListEffectiveExchangeRates(context.Background(), now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, old.ID, findExchangeRate(t, rates, util.EUR, util.USD).ID)
+}
+
+func findExchangeRate(t *testing.T, rates []ExchangeRate, base string, quote string) ExchangeRate {
+	for _, rate := range rates {
+		if rate.BaseCurrency == base && rate.QuoteCurrency == quote {
+			return rate
+		}
+	}
+	require.FailNow(t, "exchange rate not found")
+	return ExchangeRate{}
+}
+
+func TestUpsertExchangeRate(t *testing.T) {
+	effectiveAt := time.Now().Add(-time.Duration(util.RandomInt(1, 1000000)) * time.Second).Truncate(time.Second)
+	rate1 := createRandomExchangeRate(t, util.USD, util.RUB, effectiveAt)
+
+	rate2, err := testQueries.UpsertExchangeRate(context.Background(), UpsertExchangeRateParams{
+		BaseCurrency:  rate1.BaseCurrency,
+		QuoteCurrency: rate1.QuoteCurrency,
+		Rate:          "2.0000000000",
+		Source:        rate1.Source,
+		EffectiveAt:   rate1.EffectiveAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, rate1.ID, rate2.ID)
+	require.Equal(t, "2.0000000000", rate2.Rate)
+}
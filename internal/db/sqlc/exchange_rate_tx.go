@@ -0,0 +1,25 @@
+package db
+
+import "context"
+
+// ImportExchangeRatesTx upserts all rates in a single transaction, so a broken file never leaves a partial import.
+// Rates are kept per effective date, importing a newer file adds history instead of overwriting it.
+func (s *SQLStore) ImportExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) ([]ExchangeRate, error) {
+	var result []ExchangeRate
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		result = make([]ExchangeRate, 0, len(rates))
+
+		for _, arg := range rates {
+			rate, err := q.UpsertExchangeRate(ctx, arg)
+			if err != nil {
+				return err
+			}
+			result = append(result, rate)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"gobank/internal/fees"
+)
+
+type TransferFees struct {
+	Fees  []fees.Fee `json:"fees"`
+	Total int64      `json:"total"`
+}
+
+// PreviewTransferFees evaluates the fee rules on a transfer from sender, as they are charged when the transfer is made.
+// Transfers, batch lines, currency conversions and withdrawals are charged, money coming back or going to the bank is not.
+func (s *SQLStore) PreviewTransferFees(sender Account, arg TransferTxParams) TransferFees {
+	var res TransferFees
+
+	t := fees.Transfer{
+		Currency: sender.Currency,
+		Amount:   arg.Amount,
+	}
+
+	switch {
+	case (arg.Kind == "" || arg.Kind == TransferKindTransfer) && arg.ExchangeRate.Valid:
+		t.Type = fees.TypeFxTransfer
+	case arg.Kind == "" || arg.Kind == TransferKindTransfer || arg.Kind == TransferKindBatch:
+		t.Type = fees.TypeTransfer
+	case arg.Kind == TransferKindWithdrawal:
+		t.Type = fees.TypeWithdrawal
+	default:
+		return res
+	}
+
+	res.Fees, res.Total = s.fees.Evaluate(t)
+	return res
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/fees"
+	"gobank/internal/util"
+	"testing"
+)
+
+func newFeeTestStore(t *testing.T) Store {
+	config, err := util.LoadConfig("../../..")
+	require.NoError(t, err)
+
+	config.FeeRules = []fees.Rule{
+		{Name: "transfer", Type: fees.TypeTransfer, Charge: fees.ChargeFlat, Flat: 10},
+		{Name: "withdrawal", Type: fees.TypeWithdrawal, Charge: fees.ChargePercentage, RateBps: 100, Min: 5},
+	}
+	return NewSQLStore(testDB, config)
+}
+
+func TestTransferTxFees(t *testing.T) {
+	store := newFeeTestStore(t)
+	sender, recipient := createRandomAccountPair(t)
+
+	feesAccount, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Kind:     AccountKindFees,
+		Currency: sender.Currency,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      100,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []fees.Fee{{Rule: "transfer", Amount: 10}}, result.Fees)
+	require.NotNil(t, result.FeeTransfer)
+	require.Equal(t, TransferKindFee, result.FeeTransfer.Kind)
+	require.Equal(t, feesAccount.ID, result.FeeTransfer.RecipientID)
+	require.Equal(t, result.Transfer.ID, result.FeeTransfer.ParentID.Int64)
+	require.Equal(t, sender.Balance-110, result.SenderAccount.Balance)
+	require.Equal(t, recipient.Balance+100, result.RecipientAccount.Balance)
+
+	// the fee has to be covered too
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      result.SenderAccount.Balance,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestReverseWithdrawalTxFees(t *testing.T) {
+	store := newFeeTestStore(t)
+	account := createRandomAccountForUser(t, createRandomUser(t), util.RandomCurrency())
+
+	withdrawal, err := store.WithdrawalTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      1000,
+		ExternalRef: uuid.NewString(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), withdrawal.FeeTransfer.Amount)
+	require.Equal(t, account.Balance-1010, withdrawal.SenderAccount.Balance)
+
+	reversal, err := store.ReverseWithdrawalTx(context.Background(), withdrawal.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, reversal.RecipientAccount.Balance)
+}
+
+func TestPreviewTransferFees(t *testing.T) {
+	store := newFeeTestStore(t)
+	sender := Account{Currency: util.EUR}
+
+	fee := store.PreviewTransferFees(sender, TransferTxParams{Amount: 100})
+	require.Equal(t, int64(10), fee.Total)
+
+	fee = store.PreviewTransferFees(sender, TransferTxParams{Amount: 100, Kind: TransferKindWithdrawal})
+	require.Equal(t, int64(5), fee.Total)
+
+	fee = store.PreviewTransferFees(sender, TransferTxParams{Amount: 100, Kind: TransferKindRefund})
+	require.Zero(t, fee.Total)
+	require.Empty(t, fee.Fees)
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrWithdrawalNotReversible = errors.New("only withdrawals can be reversed, and only once")
+
+type FundingTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	// ExternalRef identifies the operation at the funding provider, a reference is only used once.
+	ExternalRef string `json:"external_ref"`
+	// CreatedBy is the account member who withdraws the money, see TransferTxParams.
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+// DepositTx credits an account with money collected by the funding provider,
+// as a transfer from the settlement account of its currency.
+func (s *SQLStore) DepositTx(ctx context.Context, arg FundingTxParams) (TransferTxResult, error) {
+	return s.fundingTx(ctx, arg, TransferKindDeposit)
+}
+
+// WithdrawalTx debits an account before the funding provider pays the money out,
+// as a transfer to the settlement account of its currency.
+// ErrInsufficientFunds is returned if the account overdraft policy doesn't allow the amount,
+// ErrLimitExceeded if it doesn't fit in the transfer limits, withdrawals count against them like transfers.
+func (s *SQLStore) WithdrawalTx(ctx context.Context, arg FundingTxParams) (TransferTxResult, error) {
+	return s.fundingTx(ctx, arg, TransferKindWithdrawal)
+}
+
+func (s *SQLStore) fundingTx(ctx context.Context, arg FundingTxParams, kind string) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	account, err := s.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	settlement, err := s.settlementAccount(ctx, account.Currency)
+	if err != nil {
+		return result, err
+	}
+
+	params := TransferTxParams{
+		SenderID:    settlement.ID,
+		RecipientID: account.ID,
+		Amount:      arg.Amount,
+		Kind:        kind,
+		ExternalRef: sql.NullString{String: arg.ExternalRef, Valid: true},
+		CreatedBy:   arg.CreatedBy,
+	}
+	if kind == TransferKindWithdrawal {
+		params.SenderID, params.RecipientID = account.ID, settlement.ID
+	}
+
+	err = s.execTx(ctx, []int64{params.SenderID, params.RecipientID}, func(q *Queries) error {
+		var err error
+		result, err = s.transfer(ctx, q, params)
+		return err
+	})
+
+	return result, err
+}
+
+// ReverseWithdrawalTx gives the money of a withdrawal the funding provider couldn't pay out back to the account,
+// with a refund of the whole withdrawal and another one of its fees.
+func (s *SQLStore) ReverseWithdrawalTx(ctx context.Context, transferID int64) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	withdrawal, err := s.GetTransfer(ctx, transferID)
+	if err != nil {
+		return result, err
+	}
+
+	if withdrawal.Kind != TransferKindWithdrawal {
+		return result, ErrWithdrawalNotReversible
+	}
+
+	parentID := sql.NullInt64{Int64: withdrawal.ID, Valid: true}
+
+	err = s.execTx(ctx, []int64{withdrawal.SenderID, withdrawal.RecipientID}, func(q *Queries) error {
+		reversed, err := q.GetRefundTotals(ctx, parentID)
+		if err != nil {
+			return err
+		}
+
+		if reversed.Amount > 0 {
+			return ErrWithdrawalNotReversible
+		}
+
+		result, err = s.transfer(ctx, q, TransferTxParams{
+			SenderID:    withdrawal.RecipientID,
+			RecipientID: withdrawal.SenderID,
+			Amount:      withdrawal.Amount,
+			Kind:        TransferKindRefund,
+			ParentID:    parentID,
+		})
+		if err != nil {
+			return err
+		}
+
+		fee, err := q.GetFeeTransfer(ctx, parentID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		feeRefund, err := s.transfer(ctx, q, TransferTxParams{
+			SenderID:    fee.RecipientID,
+			RecipientID: fee.SenderID,
+			Amount:      fee.Amount,
+			Kind:        TransferKindRefund,
+			ParentID:    sql.NullInt64{Int64: fee.ID, Valid: true},
+		})
+		result.RecipientAccount = feeRefund.RecipientAccount
+		return err
+	})
+
+	return result, err
+}
+
+func (s *SQLStore) settlementAccount(ctx context.Context, currency string) (Account, error) {
+	account, err := s.GetSystemAccount(ctx, GetSystemAccountParams{
+		Kind:     AccountKindSettlement,
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return account, fmt.Errorf("no %s settlement account", currency)
+	}
+	return account, err
+}
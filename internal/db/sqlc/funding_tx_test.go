@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+)
+
+func TestDepositTx(t *testing.T) {
+	account := createRandomAccount(t)
+	ref := uuid.NewString()
+
+	settlement, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Kind:     AccountKindSettlement,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.DepositTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      100,
+		ExternalRef: ref,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferKindDeposit, result.Transfer.Kind)
+	require.Equal(t, settlement.ID, result.Transfer.SenderID)
+	require.Equal(t, ref, result.Transfer.ExternalRef.String)
+	require.Equal(t, account.Balance+100, result.RecipientAccount.Balance)
+	require.Equal(t, settlement.Balance-100, result.SenderAccount.Balance)
+
+	_, err = testStore.DepositTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      100,
+		ExternalRef: ref,
+	})
+	require.Error(t, err)
+}
+
+func TestWithdrawalTx(t *testing.T) {
+	account := createRandomAccountForUser(t, createRandomUser(t), util.RandomCurrency())
+
+	_, err := testStore.WithdrawalTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      account.Balance + 1,
+		ExternalRef: uuid.NewString(),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	withdrawal, err := testStore.WithdrawalTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      account.Balance,
+		ExternalRef: uuid.NewString(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferKindWithdrawal, withdrawal.Transfer.Kind)
+	require.Zero(t, withdrawal.SenderAccount.Balance)
+
+	reversal, err := testStore.ReverseWithdrawalTx(context.Background(), withdrawal.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferKindRefund, reversal.Transfer.Kind)
+	require.Equal(t, withdrawal.Transfer.ID, reversal.Transfer.ParentID.Int64)
+	require.Equal(t, account.Balance, reversal.RecipientAccount.Balance)
+
+	_, err = testStore.ReverseWithdrawalTx(context.Background(), withdrawal.Transfer.ID)
+	require.ErrorIs(t, err, ErrWithdrawalNotReversible)
+
+	_, err = testStore.ReverseWithdrawalTx(context.Background(), reversal.Transfer.ID)
+	require.ErrorIs(t, err, ErrWithdrawalNotReversible)
+}
+
+func TestWithdrawalTxLimits(t *testing.T) {
+	user := createRandomUser(t)
+	account := createPocket(t, user, util.EUR, "Main", true)
+
+	_, err := testQueries.UpsertUserTransferLimits(context.Background(), UpsertUserTransferLimitsParams{
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		Currency:    util.EUR,
+		DailyAmount: sql.NullInt64{Int64: 100, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.WithdrawalTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      101,
+		ExternalRef: uuid.NewString(),
+	})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = testStore.WithdrawalTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      60,
+		ExternalRef: uuid.NewString(),
+	})
+	require.NoError(t, err)
+
+	// withdrawals count in the usage like transfers
+	result, err := testStore.AccountLimitsTx(context.Background(), account)
+	require.NoError(t, err)
+	require.Equal(t, int64(60), result.Usage.DailyAmount)
+
+	_, err = testStore.WithdrawalTx(context.Background(), FundingTxParams{
+		AccountID:   account.ID,
+		Amount:      60,
+		ExternalRef: uuid.NewString(),
+	})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
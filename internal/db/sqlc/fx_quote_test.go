@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func createRandomFxQuote(t *testing.T, userID int64, from string, to string, expiresAt time.Time) FxQuote {
+	arg := CreateFxQuoteParams{
+		ID:           uuid.New(),
+		UserID:       userID,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         "0.5000000000",
+		ExpiresAt:    expiresAt,
+	}
+
+	quote, err := testQueries.CreateFxQuote(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.ID, quote.ID)
+	require.Equal(t, arg.UserID, quote.UserID)
+	require.Equal(t, arg.FromCurrency, quote.FromCurrency)
+	require.Equal(t, arg.ToCurrency, quote.ToCurrency)
+	require.Equal(t, arg.Rate, quote.Rate)
+	require.False(t, quote.UsedAt.Valid)
+
+	return quote
+}
+
+func TestUseFxQuote(t *testing.T) {
+	user := createRandomUser(t)
+	quote := createRandomFxQuote(t, user.ID, util.EUR, util.USD, time.Now().Add(time.Minute))
+
+	used, err := testQueries.UseFxQuote(context.Background(), quote.ID)
+	require.NoError(t, err)
+	require.True(t, used.UsedAt.Valid)
+
+	_, err = testQueries.UseFxQuote(context.Background(), quote.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseExpiredFxQuote(t *testing.T) {
+	user := createRandomUser(t)
+	quote := createRandomFxQuote(t, user.ID, util.EUR, util.USD, time.Now().Add(-time.Minute))
+
+	_, err := testQueries.UseFxQuote(context.Background(), quote.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
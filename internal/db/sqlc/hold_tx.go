@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldVoided   = "voided"
+	HoldExpired  = "expired"
+)
+
+var ErrHoldNotActive = errors.New("hold was already captured, voided or has expired")
+var ErrCaptureExceedsHold = errors.New("captured amount can't exceed the held amount")
+
+type HoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+	// Transfer is only set when the hold is captured.
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// AuthorizeHoldTx reserves an amount of the account available balance for a later capture by the recipient.
+// The ledger balance doesn't change until the hold is captured. The hold counts against the spend limit
+// of the member who authorizes it from now on, its capture doesn't count again.
+func (s *SQLStore) AuthorizeHoldTx(ctx context.Context, arg CreateHoldParams) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := s.execTx(ctx, []int64{arg.AccountID}, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		recipient, err := q.GetAccount(ctx, arg.RecipientID)
+		if err != nil {
+			return err
+		}
+
+		// the capture checks the statuses again, they may change while the hold is active
+		if err := s.checkAccountStatus(account, recipient, TransferKindTransfer); err != nil {
+			return err
+		}
+
+		if !account.canDebit(arg.Amount) {
+			return ErrInsufficientFunds
+		}
+
+		// the capture is a transfer, its fees count against the spend limit
+		fee := s.PreviewTransferFees(account, TransferTxParams{Amount: arg.Amount, Kind: TransferKindBetween(account, recipient)})
+		if err := checkSpendLimit(ctx, q, account, arg.CreatedBy, arg.Amount+fee.Total, time.Now()); err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountAvailableBalance(ctx, AddAccountAvailableBalanceParams{
+			ID:     arg.AccountID,
+			Amount: -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CreateHold(ctx, arg)
+		return err
+	})
+
+	return result, err
+}
+
+type CaptureHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount defaults to the held amount, the rest of a partially captured hold is released.
+	Amount int64 `json:"amount"`
+}
+
+// CaptureHoldTx settles an active hold with a transfer to its recipient.
+func (s *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, arg.HoldID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		err = lockAccounts(ctx, q, sortedUniqueIDs([]int64{hold.AccountID, hold.RecipientID}))
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountAvailableBalance(ctx, AddAccountAvailableBalanceParams{
+			ID:     hold.AccountID,
+			Amount: hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = s.transfer(ctx, q, TransferTxParams{
+			SenderID:    hold.AccountID,
+			RecipientID: hold.RecipientID,
+			Amount:      amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account = result.Transfer.SenderAccount
+		result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:             hold.ID,
+			Status:         HoldCaptured,
+			CapturedAmount: amount,
+			TransferID:     sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// VoidHoldTx cancels an active hold and releases its amount.
+func (s *SQLStore) VoidHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, holdID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		result, err = releaseHold(ctx, q, hold, HoldVoided)
+		return err
+	})
+
+	return result, err
+}
+
+// ExpireHoldTx releases the active hold that has been expired the longest.
+// Holds locked by concurrent transactions are skipped, sql.ErrNoRows is returned when no hold has expired.
+func (s *SQLStore) ExpireHoldTx(ctx context.Context, now time.Time) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		hold, err := q.ClaimExpiredHold(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		result, err = releaseHold(ctx, q, hold, HoldExpired)
+		return err
+	})
+
+	return result, err
+}
+
+// lockActiveHold locks a hold before its accounts, like ClaimExpiredHold does, so hold transactions can't deadlock.
+func lockActiveHold(ctx context.Context, q *Queries, holdID int64, now time.Time) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if hold.Status != HoldActive || !hold.ExpiresAt.After(now) {
+		return hold, ErrHoldNotActive
+	}
+
+	return hold, nil
+}
+
+func releaseHold(ctx context.Context, q *Queries, hold Hold, status string) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := lockAccounts(ctx, q, []int64{hold.AccountID})
+	if err != nil {
+		return result, err
+	}
+
+	result.Account, err = q.AddAccountAvailableBalance(ctx, AddAccountAvailableBalanceParams{
+		ID:     hold.AccountID,
+		Amount: hold.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+		ID:     hold.ID,
+		Status: status,
+	})
+	return result, err
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func authorizeRandomHold(t *testing.T, account Account, recipient Account, amount int64, expiresAt time.Time) Hold {
+	result, err := testStore.AuthorizeHoldTx(context.Background(), CreateHoldParams{
+		AccountID:   account.ID,
+		RecipientID: recipient.ID,
+		Amount:      amount,
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldActive, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+	require.Equal(t, account.Balance, result.Account.Balance)
+	require.Equal(t, account.AvailableBalance-amount, result.Account.AvailableBalance)
+
+	return result.Hold
+}
+
+func TestAuthorizeHoldTxInsufficientFunds(t *testing.T) {
+	account, recipient := createRandomAccountPair(t)
+
+	_, err := testStore.AuthorizeHoldTx(context.Background(), CreateHoldParams{
+		AccountID:   account.ID,
+		RecipientID: recipient.ID,
+		Amount:      account.AvailableBalance + 1,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	account, recipient := createRandomAccountPair(t)
+	hold := authorizeRandomHold(t, account, recipient, 100, time.Now().Add(time.Hour))
+
+	// held funds can't be spent by a transfer
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    account.ID,
+		RecipientID: recipient.ID,
+		Amount:      account.AvailableBalance - 99,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID, Amount: 101})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	result, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID, Amount: 60})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldCaptured, result.Hold.Status)
+	require.Equal(t, int64(60), result.Hold.CapturedAmount)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}, result.Hold.TransferID)
+	require.Equal(t, int64(60), result.Transfer.Transfer.Amount)
+	require.Equal(t, account.Balance-60, result.Account.Balance)
+	require.Equal(t, account.AvailableBalance-60, result.Account.AvailableBalance)
+	require.Equal(t, recipient.Balance+60, result.Transfer.RecipientAccount.Balance)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestVoidHoldTx(t *testing.T) {
+	account, recipient := createRandomAccountPair(t)
+	hold := authorizeRandomHold(t, account, recipient, 100, time.Now().Add(time.Hour))
+
+	result, err := testStore.VoidHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldVoided, result.Hold.Status)
+	require.Equal(t, account.AvailableBalance, result.Account.AvailableBalance)
+
+	_, err = testStore.VoidHoldTx(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestExpireHoldTx(t *testing.T) {
+	account, recipient := createRandomAccountPair(t)
+	hold := authorizeRandomHold(t, account, recipient, 100, time.Now().Add(-time.Second))
+
+	_, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	// other tests may have left expired holds behind
+	for {
+		result, err := testStore.ExpireHoldTx(context.Background(), time.Now())
+		require.NoError(t, err)
+		if result.Hold.ID == hold.ID {
+			require.Equal(t, HoldExpired, result.Hold.Status)
+			require.Equal(t, account.AvailableBalance, result.Account.AvailableBalance)
+			break
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func createRandomIdempotencyKey(t *testing.T, expiresAt time.Time) IdempotencyKey {
+	user := createRandomUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		UserID:      user.ID,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+		ExpiresAt:   expiresAt,
+	}
+
+	record, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.UserID, record.UserID)
+	require.Equal(t, arg.Key, record.Key)
+	require.Equal(t, arg.RequestHash, record.RequestHash)
+	require.Zero(t, record.StatusCode)
+	require.Empty(t, record.ResponseBody)
+	require.WithinDuration(t, arg.ExpiresAt, record.ExpiresAt, time.Second)
+
+	return record
+}
+
+func TestCreateIdempotencyKeyConflict(t *testing.T) {
+	record := createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		UserID:      record.UserID,
+		Key:         record.Key,
+		RequestHash: util.RandomString(64),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSaveIdempotencyKeyResponse(t *testing.T) {
+	record := createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+	body := []byte(`{"id":1}`)
+
+	err := testQueries.SaveIdempotencyKeyResponse(context.Background(), SaveIdempotencyKeyResponseParams{
+		UserID:       record.UserID,
+		Key:          record.Key,
+		StatusCode:   201,
+		ResponseBody: body,
+	})
+	require.NoError(t, err)
+
+	saved, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		UserID: record.UserID,
+		Key:    record.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(201), saved.StatusCode)
+	require.Equal(t, body, saved.ResponseBody)
+}
+
+func TestDeleteExpiredIdempotencyKey(t *testing.T) {
+	active := createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+	expired := createRandomIdempotencyKey(t, time.Now().Add(-time.Hour))
+
+	n, err := testQueries.DeleteExpiredIdempotencyKey(context.Background(), DeleteExpiredIdempotencyKeyParams{
+		UserID: active.UserID,
+		Key:    active.Key,
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	n, err = testQueries.DeleteExpiredIdempotencyKey(context.Background(), DeleteExpiredIdempotencyKeyParams{
+		UserID: expired.UserID,
+		Key:    expired.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
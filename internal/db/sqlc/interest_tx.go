@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gobank/internal/interest"
+	"time"
+)
+
+// AccrueInterestTx accrues the interest of one day, the oldest one not accrued yet of an account earning interest.
+// Only complete days are accrued, the days before today. sql.ErrNoRows is returned when every account is up to date.
+func (s *SQLStore) AccrueInterestTx(ctx context.Context, now time.Time) (InterestAccrual, error) {
+	var accrual InterestAccrual
+
+	today := sql.NullTime{Time: interest.Day(now), Valid: true}
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		account, err := q.ClaimInterestAccount(ctx, today)
+		if err != nil {
+			return err
+		}
+
+		day := account.InterestAccruedTo.Time
+		nextDay := day.AddDate(0, 0, 1)
+
+		balance, err := q.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{
+			At:        nextDay,
+			AccountID: account.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		accrual, err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID: account.ID,
+			Day:       day,
+			Balance:   balance,
+			RateBps:   account.InterestRateBps,
+			Amount:    interest.Format(interest.Daily(balance, account.InterestRateBps)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.SetAccountInterestAccruedTo(ctx, SetAccountInterestAccruedToParams{
+			ID:                account.ID,
+			InterestAccruedTo: sql.NullTime{Time: nextDay, Valid: true},
+		})
+		return err
+	})
+
+	return accrual, err
+}
+
+type CapitalizeInterestTxParams struct {
+	Now time.Time `json:"now"`
+	// SkippedIDs are accounts that failed earlier in the run, they are left for the next run.
+	SkippedIDs []int64 `json:"skipped_ids"`
+}
+
+type CapitalizeInterestTxResult struct {
+	AccountID int64 `json:"account_id"`
+	// Days is the number of accruals that were capitalized.
+	Days int64 `json:"days"`
+	// Transfer is nil when the accrued interest rounded to zero.
+	Transfer *TransferTxResult `json:"transfer"`
+}
+
+// CapitalizeInterestTx pays the interest accrued by an account before the start of the current month,
+// rounded once over the whole period, with a transfer from the system interest account of its currency.
+// sql.ErrNoRows is returned when no account has interest to capitalize. When the interest of the account
+// can't be paid, e.g. there is no interest account in its currency, an *AccountError is returned,
+// the account can be skipped so it doesn't hold up the others.
+func (s *SQLStore) CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (CapitalizeInterestTxResult, error) {
+	before := interest.MonthStart(arg.Now)
+
+	accountID, err := s.GetUncapitalizedInterestAccount(ctx, GetUncapitalizedInterestAccountParams{
+		Before:     before,
+		SkippedIds: arg.SkippedIDs,
+	})
+	if err != nil {
+		return CapitalizeInterestTxResult{}, err
+	}
+
+	result, err := s.capitalizeInterest(ctx, accountID, before)
+	if err != nil {
+		return result, &AccountError{AccountID: accountID, Err: err}
+	}
+	return result, nil
+}
+
+func (s *SQLStore) capitalizeInterest(ctx context.Context, accountID int64, before time.Time) (CapitalizeInterestTxResult, error) {
+	var result CapitalizeInterestTxResult
+
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return result, err
+	}
+
+	system, err := s.GetSystemAccount(ctx, GetSystemAccountParams{
+		Kind:     AccountKindInterest,
+		Currency: account.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return result, fmt.Errorf("no %s interest account", account.Currency)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.AccountID = account.ID
+
+	// another instance may have capitalized the account while its lock was awaited, the accruals are read after it
+	err = s.execTx(ctx, []int64{account.ID, system.ID}, func(q *Queries) error {
+		accrued, err := q.GetUncapitalizedInterest(ctx, GetUncapitalizedInterestParams{
+			AccountID: account.ID,
+			Before:    before,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Days = accrued.Days
+		if accrued.Days == 0 {
+			return nil
+		}
+
+		total, err := interest.Parse(accrued.Amount)
+		if err != nil {
+			return err
+		}
+
+		var transferID sql.NullInt64
+		if amount := interest.Round(total); amount > 0 {
+			transfer, err := s.transfer(ctx, q, TransferTxParams{
+				SenderID:    system.ID,
+				RecipientID: account.ID,
+				Amount:      amount,
+				Kind:        TransferKindInterest,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Transfer = &transfer
+			transferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+		}
+
+		return q.CapitalizeInterestAccruals(ctx, CapitalizeInterestAccrualsParams{
+			TransferID: transferID,
+			AccountID:  account.ID,
+			Before:     before,
+		})
+	})
+
+	return result, err
+}
+
+type AccruedInterest struct {
+	// Amount is exact, in fractional minor units.
+	Amount string `json:"amount"`
+	// Rounded is what would be paid if the interest was capitalized now.
+	Rounded int64 `json:"rounded"`
+	Days    int64 `json:"days"`
+}
+
+// GetAccruedInterest returns the interest accrued by an account and not paid yet.
+func (s *SQLStore) GetAccruedInterest(ctx context.Context, accountID int64) (AccruedInterest, error) {
+	var res AccruedInterest
+
+	// only complete days are accrued, so every accrual is before today
+	accrued, err := s.GetUncapitalizedInterest(ctx, GetUncapitalizedInterestParams{
+		AccountID: accountID,
+		Before:    interest.Day(time.Now()).AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return res, err
+	}
+
+	amount, err := interest.Parse(accrued.Amount)
+	if err != nil {
+		return res, err
+	}
+
+	res.Amount = interest.Format(amount)
+	res.Rounded = interest.Round(amount)
+	res.Days = accrued.Days
+	return res, nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/interest"
+	"gobank/internal/util"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestInterestTx(t *testing.T) {
+	account := createRandomAccountForUser(t, createRandomUser(t), util.RandomCurrency())
+	now := time.Now()
+	days := 3
+
+	account, err := testQueries.SetAccountInterestRate(context.Background(), SetAccountInterestRateParams{
+		ID:              account.ID,
+		InterestRateBps: 3650,
+		Today:           sql.NullTime{Time: interest.Day(now).AddDate(0, 0, -days), Valid: true},
+	})
+	require.NoError(t, err)
+
+	// other tests may have left accounts earning interest behind
+	var accruals []InterestAccrual
+	for {
+		accrual, err := testStore.AccrueInterestTx(context.Background(), now)
+		if err == sql.ErrNoRows {
+			break
+		}
+		require.NoError(t, err)
+		if accrual.AccountID == account.ID {
+			accruals = append(accruals, accrual)
+		}
+	}
+
+	require.Len(t, accruals, days)
+	for i, accrual := range accruals {
+		require.True(t, interest.Day(now).AddDate(0, 0, i-days).Equal(accrual.Day))
+		require.Equal(t, account.Balance, accrual.Balance)
+		require.Equal(t, interest.Format(big.NewRat(account.Balance, 1000)), accrual.Amount)
+	}
+
+	expected := big.NewRat(account.Balance*int64(days), 1000)
+	accrued, err := testStore.GetAccruedInterest(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(days), accrued.Days)
+	require.Equal(t, interest.Format(expected), accrued.Amount)
+	require.Equal(t, interest.Round(expected), accrued.Rounded)
+
+	// a month later every accrual belongs to a complete month
+	var capitalized *CapitalizeInterestTxResult
+	for {
+		result, err := testStore.CapitalizeInterestTx(context.Background(), CapitalizeInterestTxParams{Now: now.AddDate(0, 1, 0)})
+		if err == sql.ErrNoRows {
+			break
+		}
+		require.NoError(t, err)
+		if result.AccountID == account.ID {
+			capitalized = &result
+		}
+	}
+
+	require.NotNil(t, capitalized)
+	require.Equal(t, int64(days), capitalized.Days)
+	require.NotNil(t, capitalized.Transfer)
+	require.Equal(t, TransferKindInterest, capitalized.Transfer.Transfer.Kind)
+	require.Equal(t, interest.Round(expected), capitalized.Transfer.Transfer.Amount)
+	require.Equal(t, account.Balance+interest.Round(expected), capitalized.Transfer.RecipientAccount.Balance)
+
+	accrued, err = testStore.GetAccruedInterest(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, accrued.Days)
+	require.Zero(t, accrued.Rounded)
+}
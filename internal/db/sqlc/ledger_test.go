@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLedgerQueries(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      10,
+	})
+	require.NoError(t, err)
+
+	orphan, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: sender.ID,
+		Amount:    5,
+	})
+	require.NoError(t, err)
+
+	balances, err := testQueries.ListAccountLedgerBalances(context.Background(), ListAccountLedgerBalancesParams{
+		AfterID:  sender.ID - 1,
+		PageSize: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, sender.ID, balances[0].ID)
+	require.Equal(t, sender.Balance-10, balances[0].Balance)
+	require.Equal(t, int64(-5), balances[0].EntriesBalance)
+
+	entries, err := testQueries.ListOrphanEntries(context.Background(), ListOrphanEntriesParams{
+		AfterAccountID: sender.ID - 1,
+		LastAccountID:  sender.ID,
+	})
+	require.NoError(t, err)
+	require.Contains(t, entries, orphan)
+
+	transfers, err := testQueries.ListUnbalancedTransfers(context.Background(), ListUnbalancedTransfersParams{
+		AfterAccountID: sender.ID - 1,
+		LastAccountID:  sender.ID,
+	})
+	require.NoError(t, err)
+	for _, transfer := range transfers {
+		require.NotEqual(t, result.Transfer.ID, transfer.ID)
+	}
+
+	conversions, err := testQueries.ListCurrencyConversions(context.Background(), ListCurrencyConversionsParams{
+		AfterAccountID: sender.ID - 1,
+		LastAccountID:  sender.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, conversions)
+}
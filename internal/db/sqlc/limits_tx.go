@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gobank/internal/util"
+	"time"
+)
+
+var ErrLimitExceeded = errors.New("transfer limit exceeded")
+
+// ErrSpendLimitExceeded wraps ErrLimitExceeded, it is returned when a debit made by an account member
+// doesn't fit in what is left of their spend limit for the day.
+var ErrSpendLimitExceeded = fmt.Errorf("%w: amount and fees are over the daily spend limit of the account member", ErrLimitExceeded)
+
+type AccountLimitsTxResult struct {
+	Limits util.Limits `json:"limits"`
+	// Usage counts the transfers sent in the current UTC day and month, from all the accounts of the owner
+	// in the currency unless the account has limits of its own.
+	Usage      GetTransferUsageRow `json:"usage"`
+	DayStart   time.Time           `json:"day_start"`
+	MonthStart time.Time           `json:"month_start"`
+}
+
+// AccountLimitsTx reads the limits of an account and how much of them was used.
+// Account overrides take precedence over user overrides, which take precedence over the configured defaults.
+func (s *SQLStore) AccountLimitsTx(ctx context.Context, account Account) (AccountLimitsTxResult, error) {
+	var result AccountLimitsTxResult
+
+	err := s.readTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = s.accountLimits(ctx, q, account, time.Now())
+		return err
+	})
+
+	return result, err
+}
+
+func (s *SQLStore) accountLimits(ctx context.Context, q *Queries, account Account, now time.Time) (AccountLimitsTxResult, error) {
+	result := AccountLimitsTxResult{
+		Limits: s.config.TransferLimits[account.Currency],
+	}
+
+	overrides, err := q.ListTransferLimitOverrides(ctx, ListTransferLimitOverridesParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		UserID:    sql.NullInt64{Int64: account.OwnerID, Valid: true},
+		Currency:  account.Currency,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// the defaults and user overrides are shared by all the accounts of the owner in the currency, so are their
+	// usages, otherwise opening another account would give a fresh allowance
+	usageOwnerID := sql.NullInt64{Int64: account.OwnerID, Valid: true}
+	for _, override := range overrides {
+		result.Limits = override.apply(result.Limits)
+		if override.AccountID.Valid {
+			usageOwnerID.Valid = false
+		}
+	}
+
+	now = now.UTC()
+	result.DayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	result.MonthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	result.Usage, err = q.GetTransferUsage(ctx, GetTransferUsageParams{
+		DayStart:   result.DayStart,
+		AccountID:  account.ID,
+		OwnerID:    usageOwnerID,
+		Currency:   account.Currency,
+		MonthStart: result.MonthStart,
+	})
+	return result, err
+}
+
+// lockUsage serializes the transactions that check the limits of the accounts of an owner in a currency
+// until they commit. The usage is shared by these accounts, so two transfers from different accounts
+// would both read the usage before the other one and could exceed the limits together.
+func lockUsage(ctx context.Context, q *Queries, account Account) error {
+	return q.LockTransferUsage(ctx, LockTransferUsageParams{
+		Currency: account.Currency,
+		OwnerID:  account.OwnerID,
+	})
+}
+
+func (l TransferLimit) apply(limits util.Limits) util.Limits {
+	if l.PerTransfer.Valid {
+		limits.PerTransfer = l.PerTransfer.Int64
+	}
+	if l.DailyAmount.Valid {
+		limits.DailyAmount = l.DailyAmount.Int64
+	}
+	if l.MonthlyAmount.Valid {
+		limits.MonthlyAmount = l.MonthlyAmount.Int64
+	}
+	if l.DailyCount.Valid {
+		limits.DailyCount = l.DailyCount.Int64
+	}
+	if l.MonthlyCount.Valid {
+		limits.MonthlyCount = l.MonthlyCount.Int64
+	}
+	return limits
+}
+
+// checkLimits returns an error wrapping ErrLimitExceeded if a transfer of amount doesn't fit in the limits.
+func checkLimits(limits util.Limits, usage GetTransferUsageRow, amount int64) error {
+	switch {
+	case limits.PerTransfer > 0 && amount > limits.PerTransfer:
+		return fmt.Errorf("%w: at most %d per transfer", ErrLimitExceeded, limits.PerTransfer)
+	case limits.DailyAmount > 0 && usage.DailyAmount+amount > limits.DailyAmount:
+		return fmt.Errorf("%w: at most %d per day, %d left", ErrLimitExceeded, limits.DailyAmount, limits.DailyAmount-usage.DailyAmount)
+	case limits.MonthlyAmount > 0 && usage.MonthlyAmount+amount > limits.MonthlyAmount:
+		return fmt.Errorf("%w: at most %d per month, %d left", ErrLimitExceeded, limits.MonthlyAmount, limits.MonthlyAmount-usage.MonthlyAmount)
+	case limits.DailyCount > 0 && usage.DailyCount >= limits.DailyCount:
+		return fmt.Errorf("%w: at most %d transfers per day", ErrLimitExceeded, limits.DailyCount)
+	case limits.MonthlyCount > 0 && usage.MonthlyCount >= limits.MonthlyCount:
+		return fmt.Errorf("%w: at most %d transfers per month", ErrLimitExceeded, limits.MonthlyCount)
+	}
+	return nil
+}
+
+// checkSpendLimit checks that a debit of amount, fees included, made by a member of the account fits in their spend limit
+// for the current UTC day, with the transfers they made from the account and the holds they authorized on it.
+// The holder has no spend limit, neither do the debits made by the system.
+func checkSpendLimit(ctx context.Context, q *Queries, account Account, createdBy sql.NullInt64, amount int64, now time.Time) error {
+	if !createdBy.Valid || createdBy.Int64 == account.OwnerID {
+		return nil
+	}
+
+	member, err := q.GetAccountMember(ctx, GetAccountMemberParams{
+		AccountID: account.ID,
+		UserID:    createdBy.Int64,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !member.SpendLimit.Valid {
+		return nil
+	}
+
+	now = now.UTC()
+	used, err := q.GetMemberSpendUsage(ctx, GetMemberSpendUsageParams{
+		AccountID: account.ID,
+		UserID:    createdBy,
+		DayStart:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return err
+	}
+
+	if used+amount > member.SpendLimit.Int64 {
+		return ErrSpendLimitExceeded
+	}
+	return nil
+}
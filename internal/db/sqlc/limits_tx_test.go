@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func TestCheckLimits(t *testing.T) {
+	limits := util.Limits{
+		PerTransfer:   100,
+		DailyAmount:   300,
+		MonthlyAmount: 1000,
+		DailyCount:    5,
+		MonthlyCount:  20,
+	}
+
+	require.NoError(t, checkLimits(limits, GetTransferUsageRow{}, 100))
+	require.NoError(t, checkLimits(util.Limits{}, GetTransferUsageRow{DailyCount: 1000}, 1e9))
+
+	exceeded := []struct {
+		usage  GetTransferUsageRow
+		amount int64
+	}{
+		{GetTransferUsageRow{}, 101},
+		{GetTransferUsageRow{DailyAmount: 250, MonthlyAmount: 250}, 51},
+		{GetTransferUsageRow{DailyAmount: 0, MonthlyAmount: 950}, 51},
+		{GetTransferUsageRow{DailyCount: 5, MonthlyCount: 5}, 1},
+		{GetTransferUsageRow{DailyCount: 0, MonthlyCount: 20}, 1},
+	}
+
+	for _, tc := range exceeded {
+		require.ErrorIs(t, checkLimits(limits, tc.usage, tc.amount), ErrLimitExceeded)
+	}
+}
+
+func TestAccountLimitsTx(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+
+	_, err := testQueries.UpsertUserTransferLimits(context.Background(), UpsertUserTransferLimitsParams{
+		UserID:      sql.NullInt64{Int64: sender.OwnerID, Valid: true},
+		Currency:    sender.Currency,
+		PerTransfer: sql.NullInt64{Int64: 50, Valid: true},
+		DailyCount:  sql.NullInt64{Int64: 10, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpsertAccountTransferLimits(context.Background(), UpsertAccountTransferLimitsParams{
+		AccountID:   sql.NullInt64{Int64: sender.ID, Valid: true},
+		Currency:    sender.Currency,
+		DailyAmount: sql.NullInt64{Int64: 70, Valid: true},
+		DailyCount:  sql.NullInt64{Int64: 2, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      40,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.AccountLimitsTx(context.Background(), sender)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(50), result.Limits.PerTransfer)
+	require.Equal(t, int64(70), result.Limits.DailyAmount)
+	require.Equal(t, int64(2), result.Limits.DailyCount)
+	require.Equal(t, int64(40), result.Usage.DailyAmount)
+	require.Equal(t, int64(1), result.Usage.DailyCount)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      31,
+	})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      30,
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountLimitsTxSharedByPockets(t *testing.T) {
+	user := createRandomUser(t)
+	main := createPocket(t, user, util.EUR, "Main", true)
+	savings := createPocket(t, user, util.EUR, "Savings", false)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), util.EUR)
+
+	_, err := testQueries.UpsertUserTransferLimits(context.Background(), UpsertUserTransferLimitsParams{
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		Currency:    util.EUR,
+		DailyAmount: sql.NullInt64{Int64: 100, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    main.ID,
+		RecipientID: recipient.ID,
+		Amount:      80,
+	})
+	require.NoError(t, err)
+
+	// the second pocket shares the daily allowance of the first one
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    savings.ID,
+		RecipientID: recipient.ID,
+		Amount:      30,
+	})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	result, err := testStore.AccountLimitsTx(context.Background(), savings)
+	require.NoError(t, err)
+	require.Equal(t, int64(80), result.Usage.DailyAmount)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    savings.ID,
+		RecipientID: recipient.ID,
+		Amount:      20,
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountLimitsTxConcurrentPockets(t *testing.T) {
+	user := createRandomUser(t)
+	pockets := []Account{
+		createPocket(t, user, util.EUR, "Main", true),
+		createPocket(t, user, util.EUR, "Savings", false),
+	}
+
+	_, err := testQueries.UpsertUserTransferLimits(context.Background(), UpsertUserTransferLimitsParams{
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		Currency:    util.EUR,
+		DailyAmount: sql.NullInt64{Int64: 100, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// a recipient per pocket, so the transfers don't wait for each other on the account locks
+	errs := make(chan error)
+	for _, pocket := range pockets {
+		senderID := pocket.ID
+		recipientID := createRandomAccountForUser(t, createRandomUser(t), util.EUR).ID
+		go func() {
+			_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+				SenderID:    senderID,
+				RecipientID: recipientID,
+				Amount:      80,
+			})
+			errs <- err
+		}()
+	}
+
+	// the pockets share the usage, only one of the transfers fits in the daily limit
+	var exceeded int
+	for range pockets {
+		err := <-errs
+		if errors.Is(err, ErrLimitExceeded) {
+			exceeded++
+			continue
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, exceeded)
+}
+
+func TestSpendLimitTx(t *testing.T) {
+	holder := createRandomUser(t)
+	account := createPocket(t, holder, util.EUR, "Household", false)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), util.EUR)
+
+	// room for two debits of 30 with their fees
+	fee := testStore.PreviewTransferFees(account, TransferTxParams{Amount: 30, Kind: TransferKindTransfer}).Total
+	user := createRandomUser(t)
+	_, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID:  account.ID,
+		UserID:     user.ID,
+		Role:       AccountMemberSpender,
+		SpendLimit: sql.NullInt64{Int64: 2 * (30 + fee), Valid: true},
+		Status:     AccountMemberActive,
+		InvitedBy:  sql.NullInt64{Int64: holder.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	createdBy := sql.NullInt64{Int64: user.ID, Valid: true}
+
+	transfer := func(createdBy sql.NullInt64) error {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			SenderID:    account.ID,
+			RecipientID: recipient.ID,
+			Amount:      30,
+			CreatedBy:   createdBy,
+		})
+		return err
+	}
+
+	require.NoError(t, transfer(createdBy))
+
+	// a hold counts against the allowance when it is authorized
+	_, err = testStore.AuthorizeHoldTx(context.Background(), CreateHoldParams{
+		AccountID:   account.ID,
+		RecipientID: recipient.ID,
+		Amount:      30,
+		ExpiresAt:   time.Now().Add(time.Hour),
+		CreatedBy:   createdBy,
+	})
+	require.NoError(t, err)
+
+	err = transfer(createdBy)
+	require.ErrorIs(t, err, ErrSpendLimitExceeded)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	// the holder has no spend limit
+	require.NoError(t, transfer(sql.NullInt64{Int64: holder.ID, Valid: true}))
+	require.NoError(t, transfer(sql.NullInt64{}))
+}
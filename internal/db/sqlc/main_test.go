@@ -10,19 +10,22 @@ import (
 )
 
 var testQueries *Queries
+var testDB *sql.DB
+var testStore Store
 
 func TestMain(m *testing.M) {
-	config, err := util.LoadConfig("../..")
+	config, err := util.LoadConfig("../../..")
 	if err != nil {
 		log.Fatal("cannot load config:", err)
 	}
 
-	testDB, err := sql.Open(config.DBDriver, config.DBSource)
+	testDB, err = sql.Open(config.DBDriver, config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to db:", err)
 	}
 
 	testQueries = New(testDB)
+	testStore = NewSQLStore(testDB, config)
 
 	os.Exit(m.Run())
 }
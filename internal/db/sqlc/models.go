@@ -5,24 +5,183 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	OwnerID   int64     `json:"owner_id"`
+	ID      int64 `json:"id"`
+	OwnerID int64 `json:"owner_id"`
+	// ledger balance, the sum of the account entries
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// ledger balance minus the amount of active holds
+	AvailableBalance int64 `json:"available_balance"`
+	// customer, or the purpose of a system account, e.g. fees
+	Kind string `json:"kind"`
+	// none, limit or unlimited
+	OverdraftPolicy string `json:"overdraft_policy"`
+	// how far below zero the available balance can go under the limit policy
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// yearly interest rate in basis points, accrued daily on the end of day balance
+	InterestRateBps int64 `json:"interest_rate_bps"`
+	// interest is accrued for the days before this one, null without an interest rate
+	InterestAccruedTo sql.NullTime `json:"interest_accrued_to"`
+	// active, frozen or closed, only active accounts can send money
+	Status string `json:"status"`
+	// nickname of the account, unique among the open accounts of its owner
+	Name string `json:"name"`
+	// what the owner keeps the money of the account for, e.g. savings
+	Purpose string `json:"purpose"`
+	// the account money sent to the owner by username or email goes to, at most one per currency
+	IsPrimary bool `json:"is_primary"`
+}
+
+type AccountMember struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	UserID    int64 `json:"user_id"`
+	// owner, spender or viewer
+	Role string `json:"role"`
+	// largest amount a spender can send from the account per UTC day, fees and holds included, null for no limit
+	SpendLimit sql.NullInt64 `json:"spend_limit"`
+	// invited until the user accepts, then active
+	Status     string        `json:"status"`
+	InvitedBy  sql.NullInt64 `json:"invited_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+	AcceptedAt sql.NullTime  `json:"accepted_at"`
+}
+
+type AccountStatusChange struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"account_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+	// the user who changed the status, the owner or an admin
+	ChangedBy int64     `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Entry struct {
+	ID         int64         `json:"id"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	CreatedAt  time.Time     `json:"created_at"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type ExchangeRate struct {
+	ID            int64  `json:"id"`
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	// units of quote currency for one unit of base currency
+	Rate        string    `json:"rate"`
+	Source      string    `json:"source"`
+	EffectiveAt time.Time `json:"effective_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FxQuote struct {
+	ID           uuid.UUID    `json:"id"`
+	UserID       int64        `json:"user_id"`
+	FromCurrency string       `json:"from_currency"`
+	ToCurrency   string       `json:"to_currency"`
+	Rate         string       `json:"rate"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	UsedAt       sql.NullTime `json:"used_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type Hold struct {
+	ID             int64  `json:"id"`
+	AccountID      int64  `json:"account_id"`
+	RecipientID    int64  `json:"recipient_id"`
+	Amount         int64  `json:"amount"`
+	Status         string `json:"status"`
+	CapturedAmount int64  `json:"captured_amount"`
+	// the transfer the hold was captured into
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	// the account member who authorized the hold, it is voided when they are removed
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+type IdempotencyKey struct {
+	UserID       int64     `json:"user_id"`
+	Key          string    `json:"key"`
+	RequestHash  string    `json:"request_hash"`
+	StatusCode   int32     `json:"status_code"`
+	ResponseBody []byte    `json:"response_body"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type InterestAccrual struct {
 	ID        int64     `json:"id"`
 	AccountID int64     `json:"account_id"`
-	Amount    int64     `json:"amount"`
+	Day       time.Time `json:"day"`
+	// end of day balance the interest is computed on
+	Balance int64 `json:"balance"`
+	RateBps int64 `json:"rate_bps"`
+	// interest in fractional minor units, rounded once the month is capitalized
+	Amount string `json:"amount"`
+	// the interest transfer that paid the accrual, null if it rounded to zero
+	TransferID    sql.NullInt64 `json:"transfer_id"`
+	CapitalizedAt sql.NullTime  `json:"capitalized_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+type PaymentRequest struct {
+	ID          int64 `json:"id"`
+	RequesterID int64 `json:"requester_id"`
+	PayerID     int64 `json:"payer_id"`
+	// account of the requester the money is paid into
+	RecipientAccountID int64  `json:"recipient_account_id"`
+	Amount             int64  `json:"amount"`
+	Currency           string `json:"currency"`
+	Note               string `json:"note"`
+	// pending, accepted, declined, cancelled or expired
+	Status string `json:"status"`
+	// the transfer that paid the request once accepted
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	ResolvedAt sql.NullTime  `json:"resolved_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type ScheduledTransfer struct {
+	ID          int64 `json:"id"`
+	SenderID    int64 `json:"sender_id"`
+	RecipientID int64 `json:"recipient_id"`
+	Amount      int64 `json:"amount"`
+	// once, daily, weekly, monthly or yearly, counted from start_at
+	Recurrence string       `json:"recurrence"`
+	StartAt    time.Time    `json:"start_at"`
+	EndAt      sql.NullTime `json:"end_at"`
+	// null once the schedule is completed or cancelled
+	NextRunAt sql.NullTime `json:"next_run_at"`
+	// failed attempts of the current occurrence
+	Attempts  int32     `json:"attempts"`
+	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
+	// the account member who scheduled the transfer, it is cancelled when they are removed
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64          `json:"id"`
+	ScheduledTransferID int64          `json:"scheduled_transfer_id"`
+	Attempt             int32          `json:"attempt"`
+	Status              string         `json:"status"`
+	TransferID          sql.NullInt64  `json:"transfer_id"`
+	Error               sql.NullString `json:"error"`
+	CreatedAt           time.Time      `json:"created_at"`
 }
 
 type Session struct {
@@ -38,11 +197,66 @@ type Session struct {
 }
 
 type Transfer struct {
-	ID          int64     `json:"id"`
-	SenderID    int64     `json:"sender_id"`
-	RecipientID int64     `json:"recipient_id"`
-	Amount      int64     `json:"amount"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              int64          `json:"id"`
+	SenderID        int64          `json:"sender_id"`
+	RecipientID     int64          `json:"recipient_id"`
+	Amount          int64          `json:"amount"`
+	CreatedAt       time.Time      `json:"created_at"`
+	RecipientAmount int64          `json:"recipient_amount"`
+	ExchangeRate    sql.NullString `json:"exchange_rate"`
+	QuoteID         uuid.NullUUID  `json:"quote_id"`
+	Kind            string         `json:"kind"`
+	// the transfer this one refunds or charges the fees of
+	ParentID sql.NullInt64 `json:"parent_id"`
+	// reference of a deposit or withdrawal at the funding provider
+	ExternalRef sql.NullString `json:"external_ref"`
+	BatchID     sql.NullInt64  `json:"batch_id"`
+	// free text of the sender, what the payment is for
+	Memo string `json:"memo"`
+	// reference of the sender, e.g. an invoice number, unlike external_ref it is not unique
+	Reference string `json:"reference"`
+	// flat object of string values set by the sender
+	Metadata json.RawMessage `json:"metadata"`
+	// the account member who made the transfer, null for transfers made by the system
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+type TransferBatch struct {
+	ID       int64  `json:"id"`
+	SenderID int64  `json:"sender_id"`
+	Currency string `json:"currency"`
+	// completed, or rejected when no line was executed
+	Status      string         `json:"status"`
+	LineCount   int64          `json:"line_count"`
+	TotalAmount int64          `json:"total_amount"`
+	TotalFee    int64          `json:"total_fee"`
+	Error       sql.NullString `json:"error"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type TransferBatchLine struct {
+	ID      int64 `json:"id"`
+	BatchID int64 `json:"batch_id"`
+	// position of the line in the batch, from 1
+	Line int64 `json:"line"`
+	// as requested, it may not be an existing account in a rejected batch
+	RecipientID int64          `json:"recipient_id"`
+	Amount      int64          `json:"amount"`
+	TransferID  sql.NullInt64  `json:"transfer_id"`
+	Error       sql.NullString `json:"error"`
+}
+
+type TransferLimit struct {
+	ID            int64         `json:"id"`
+	UserID        sql.NullInt64 `json:"user_id"`
+	AccountID     sql.NullInt64 `json:"account_id"`
+	Currency      string        `json:"currency"`
+	PerTransfer   sql.NullInt64 `json:"per_transfer"`
+	DailyAmount   sql.NullInt64 `json:"daily_amount"`
+	MonthlyAmount sql.NullInt64 `json:"monthly_amount"`
+	DailyCount    sql.NullInt64 `json:"daily_count"`
+	MonthlyCount  sql.NullInt64 `json:"monthly_count"`
+	CreatedAt     time.Time     `json:"created_at"`
 }
 
 type User struct {
@@ -52,4 +266,5 @@ type User struct {
 	Password          string    `json:"password"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
 }
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	AccountKindCustomer   = "customer"
+	AccountKindFees       = "fees"
+	AccountKindSettlement = "settlement"
+	AccountKindInterest   = "interest"
+)
+
+const (
+	OverdraftNone      = "none"
+	OverdraftLimit     = "limit"
+	OverdraftUnlimited = "unlimited"
+)
+
+var ErrInvalidOverdraft = errors.New("overdraft limit is only allowed with the limit policy")
+
+// canDebit reports whether the available balance can cover amount under the account overdraft policy.
+func (a Account) canDebit(amount int64) bool {
+	switch a.OverdraftPolicy {
+	case OverdraftUnlimited:
+		return true
+	case OverdraftLimit:
+		return a.AvailableBalance-amount >= -a.OverdraftLimit
+	default:
+		return a.AvailableBalance >= amount
+	}
+}
+
+// UpdateOverdraftTx changes the overdraft policy of an account.
+// An account that is already overdrawn keeps its balance, it just can't be debited further.
+func (s *SQLStore) UpdateOverdraftTx(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error) {
+	var account Account
+
+	if arg.OverdraftPolicy != OverdraftLimit && arg.OverdraftLimit != 0 {
+		return account, ErrInvalidOverdraft
+	}
+
+	err := s.execTx(ctx, []int64{arg.ID}, func(q *Queries) error {
+		var err error
+		account, err = q.UpdateAccountOverdraft(ctx, arg)
+		return err
+	})
+
+	return account, err
+}
+
+type ChargeOverdraftFeeTxParams struct {
+	Now time.Time `json:"now"`
+	// RateBps is the daily fee in basis points of the overdrawn balance.
+	RateBps int64 `json:"rate_bps"`
+	// SkippedIDs are accounts that failed earlier in the run, they are left for the next run.
+	SkippedIDs []int64 `json:"skipped_ids"`
+}
+
+// ChargeOverdraftFeeTx charges the daily overdraft fee of one overdrawn customer account,
+// as a transfer to the system fees account of its currency.
+// Each account is charged at most once per UTC day, sql.ErrNoRows is returned when none is left.
+// When the fee of the claimed account can't be charged, e.g. there is no fees account in its currency,
+// an *AccountError is returned, the account can be skipped so it doesn't hold up the others.
+func (s *SQLStore) ChargeOverdraftFeeTx(ctx context.Context, arg ChargeOverdraftFeeTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	now := arg.Now.UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		account, err := q.ClaimOverdrawnAccount(ctx, ClaimOverdrawnAccountParams{
+			DayStart:   dayStart,
+			SkippedIds: arg.SkippedIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = s.chargeOverdraftFee(ctx, q, account, arg.RateBps)
+		if err != nil {
+			return &AccountError{AccountID: account.ID, Err: err}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *SQLStore) chargeOverdraftFee(ctx context.Context, q *Queries, account Account, rateBps int64) (TransferTxResult, error) {
+	fees, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Kind:     AccountKindFees,
+		Currency: account.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return TransferTxResult{}, fmt.Errorf("no %s fees account", account.Currency)
+	}
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+
+	if err := lockAccounts(ctx, q, []int64{fees.ID}); err != nil {
+		return TransferTxResult{}, err
+	}
+
+	return s.transfer(ctx, q, TransferTxParams{
+		SenderID:    account.ID,
+		RecipientID: fees.ID,
+		Amount:      overdraftFee(account.Balance, rateBps),
+		Kind:        TransferKindOverdraftFee,
+	})
+}
+
+// overdraftFee is at least one minor unit, so small overdrafts aren't free.
+func overdraftFee(balance int64, rateBps int64) int64 {
+	fee := -balance * rateBps / 10000
+	if fee < 1 {
+		fee = 1
+	}
+	return fee
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func setOverdraft(t *testing.T, account Account, policy string, limit int64) Account {
+	account, err := testStore.UpdateOverdraftTx(context.Background(), UpdateAccountOverdraftParams{
+		ID:              account.ID,
+		OverdraftPolicy: policy,
+		OverdraftLimit:  limit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, policy, account.OverdraftPolicy)
+	require.Equal(t, limit, account.OverdraftLimit)
+	return account
+}
+
+func TestTransferTxOverdraftLimit(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+	sender = setOverdraft(t, sender, OverdraftLimit, 100)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      sender.Balance + 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-100), result.SenderAccount.Balance)
+	require.Equal(t, int64(-100), result.SenderAccount.AvailableBalance)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestUpdateOverdraftTxInvalid(t *testing.T) {
+	account := createRandomAccount(t)
+
+	_, err := testStore.UpdateOverdraftTx(context.Background(), UpdateAccountOverdraftParams{
+		ID:              account.ID,
+		OverdraftPolicy: OverdraftNone,
+		OverdraftLimit:  100,
+	})
+	require.ErrorIs(t, err, ErrInvalidOverdraft)
+}
+
+func TestChargeOverdraftFeeTx(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+	sender = setOverdraft(t, sender, OverdraftLimit, 1000)
+
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      sender.Balance + 500,
+	})
+	require.NoError(t, err)
+
+	fees, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Kind:     AccountKindFees,
+		Currency: sender.Currency,
+	})
+	require.NoError(t, err)
+
+	arg := ChargeOverdraftFeeTxParams{
+		Now:     time.Now(),
+		RateBps: 100,
+	}
+
+	// other tests may have left overdrawn accounts behind
+	var charged *TransferTxResult
+	for {
+		result, err := testStore.ChargeOverdraftFeeTx(context.Background(), arg)
+		if err == sql.ErrNoRows {
+			break
+		}
+		require.NoError(t, err)
+		if result.Transfer.SenderID == sender.ID {
+			charged = &result
+		}
+	}
+
+	require.NotNil(t, charged)
+	require.Equal(t, TransferKindOverdraftFee, charged.Transfer.Kind)
+	require.Equal(t, fees.ID, charged.Transfer.RecipientID)
+	require.Equal(t, int64(5), charged.Transfer.Amount)
+	require.Equal(t, int64(-505), charged.SenderAccount.Balance)
+
+	_, err = testStore.ChargeOverdraftFeeTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestAccountCanDebit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		account Account
+		amount  int64
+		ok      bool
+	}{
+		{"none covered", Account{OverdraftPolicy: OverdraftNone, AvailableBalance: 10}, 10, true},
+		{"none overdrawn", Account{OverdraftPolicy: OverdraftNone, AvailableBalance: 10}, 11, false},
+		{"limit covered", Account{OverdraftPolicy: OverdraftLimit, AvailableBalance: 10, OverdraftLimit: 5}, 15, true},
+		{"limit exceeded", Account{OverdraftPolicy: OverdraftLimit, AvailableBalance: 10, OverdraftLimit: 5}, 16, false},
+		{"unlimited", Account{OverdraftPolicy: OverdraftUnlimited, AvailableBalance: -1000}, 1000, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.ok, tc.account.canDebit(tc.amount))
+		})
+	}
+}
+
+func TestOverdraftFee(t *testing.T) {
+	require.Equal(t, int64(5), overdraftFee(-500, 100))
+	require.Equal(t, int64(1), overdraftFee(-10, 100))
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	PaymentRequestPending   = "pending"
+	PaymentRequestAccepted  = "accepted"
+	PaymentRequestDeclined  = "declined"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired"
+)
+
+var ErrPaymentRequestNotPending = errors.New("payment request was already accepted, declined, cancelled or has expired")
+
+type AcceptPaymentRequestTxParams struct {
+	RequestID int64 `json:"request_id"`
+	// SenderID is the account of the payer the request is paid from, in the currency of the request.
+	SenderID int64 `json:"sender_id"`
+	// CreatedBy is the account member who pays the request, see TransferTxParams.
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+type PaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest `json:"payment_request"`
+	// Transfer is only set when the request is accepted.
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// AcceptPaymentRequestTx pays a pending payment request with a transfer to the requester's account.
+// The transfer is subject to the fees and limits of any transfer, the request stays pending if it fails.
+func (s *SQLStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (PaymentRequestTxResult, error) {
+	var result PaymentRequestTxResult
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		request, err := lockPendingPaymentRequest(ctx, q, arg.RequestID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		err = lockAccounts(ctx, q, sortedUniqueIDs([]int64{arg.SenderID, request.RecipientAccountID}))
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = s.transfer(ctx, q, TransferTxParams{
+			SenderID:    arg.SenderID,
+			RecipientID: request.RecipientAccountID,
+			Amount:      request.Amount,
+			Memo:        request.Note,
+			CreatedBy:   arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.ResolvePaymentRequest(ctx, ResolvePaymentRequestParams{
+			ID:         request.ID,
+			Status:     PaymentRequestAccepted,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeclinePaymentRequestTx refuses a pending payment request, on behalf of the payer.
+func (s *SQLStore) DeclinePaymentRequestTx(ctx context.Context, requestID int64) (PaymentRequest, error) {
+	return s.closePaymentRequest(ctx, requestID, PaymentRequestDeclined)
+}
+
+// CancelPaymentRequestTx withdraws a pending payment request, on behalf of the requester.
+func (s *SQLStore) CancelPaymentRequestTx(ctx context.Context, requestID int64) (PaymentRequest, error) {
+	return s.closePaymentRequest(ctx, requestID, PaymentRequestCancelled)
+}
+
+func (s *SQLStore) closePaymentRequest(ctx context.Context, requestID int64, status string) (PaymentRequest, error) {
+	var request PaymentRequest
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		_, err := lockPendingPaymentRequest(ctx, q, requestID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		request, err = q.ResolvePaymentRequest(ctx, ResolvePaymentRequestParams{
+			ID:     requestID,
+			Status: status,
+		})
+		return err
+	})
+
+	return request, err
+}
+
+// lockPendingPaymentRequest locks a request before any account, like lockActiveHold does for holds.
+// A request past its expiry is no longer pending even if the expiry job hasn't marked it yet.
+func lockPendingPaymentRequest(ctx context.Context, q *Queries, requestID int64, now time.Time) (PaymentRequest, error) {
+	request, err := q.GetPaymentRequestForUpdate(ctx, requestID)
+	if err != nil {
+		return request, err
+	}
+
+	if request.Status != PaymentRequestPending || !request.ExpiresAt.After(now) {
+		return request, ErrPaymentRequestNotPending
+	}
+
+	return request, nil
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createRandomPaymentRequest(t *testing.T, payer Account, recipient Account, amount int64, expiresAt time.Time) PaymentRequest {
+	request, err := testQueries.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		RequesterID:        recipient.OwnerID,
+		PayerID:            payer.OwnerID,
+		RecipientAccountID: recipient.ID,
+		Amount:             amount,
+		Currency:           recipient.Currency,
+		Note:               "dinner",
+		ExpiresAt:          expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPending, request.Status)
+	require.False(t, request.TransferID.Valid)
+
+	return request
+}
+
+func TestAcceptPaymentRequestTx(t *testing.T) {
+	payer, recipient := createRandomAccountPair(t)
+	request := createRandomPaymentRequest(t, payer, recipient, 100, time.Now().Add(time.Hour))
+
+	result, err := testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		RequestID: request.ID,
+		SenderID:  payer.ID,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, PaymentRequestAccepted, result.PaymentRequest.Status)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}, result.PaymentRequest.TransferID)
+	require.True(t, result.PaymentRequest.ResolvedAt.Valid)
+	require.Equal(t, payer.Balance-100, result.Transfer.SenderAccount.Balance)
+	require.Equal(t, recipient.Balance+100, result.Transfer.RecipientAccount.Balance)
+
+	_, err = testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		RequestID: request.ID,
+		SenderID:  payer.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestNotPending)
+}
+
+func TestAcceptPaymentRequestTxInsufficientFunds(t *testing.T) {
+	payer, recipient := createRandomAccountPair(t)
+	request := createRandomPaymentRequest(t, payer, recipient, payer.Balance+1, time.Now().Add(time.Hour))
+
+	_, err := testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		RequestID: request.ID,
+		SenderID:  payer.ID,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// a failed payment leaves the request pending
+	request, err = testQueries.GetPaymentRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPending, request.Status)
+}
+
+func TestDeclineAndCancelPaymentRequestTx(t *testing.T) {
+	payer, recipient := createRandomAccountPair(t)
+
+	declined := createRandomPaymentRequest(t, payer, recipient, 100, time.Now().Add(time.Hour))
+	request, err := testStore.DeclinePaymentRequestTx(context.Background(), declined.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestDeclined, request.Status)
+
+	_, err = testStore.CancelPaymentRequestTx(context.Background(), declined.ID)
+	require.ErrorIs(t, err, ErrPaymentRequestNotPending)
+
+	cancelled := createRandomPaymentRequest(t, payer, recipient, 100, time.Now().Add(time.Hour))
+	request, err = testStore.CancelPaymentRequestTx(context.Background(), cancelled.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestCancelled, request.Status)
+}
+
+func TestExpirePaymentRequests(t *testing.T) {
+	payer, recipient := createRandomAccountPair(t)
+	request := createRandomPaymentRequest(t, payer, recipient, 100, time.Now().Add(-time.Minute))
+
+	// an expired request can't be accepted before the job marks it
+	_, err := testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		RequestID: request.ID,
+		SenderID:  payer.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestNotPending)
+
+	expired, err := testQueries.ExpirePaymentRequests(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, expired, int64(1))
+
+	request, err = testQueries.GetPaymentRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestExpired, request.Status)
+}
@@ -6,20 +6,111 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AcceptAccountMember(ctx context.Context, id int64) (AccountMember, error)
+	AddAccountAvailableBalance(ctx context.Context, arg AddAccountAvailableBalanceParams) (Account, error)
+	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	CancelAccountPaymentRequests(ctx context.Context, recipientAccountID int64) error
+	CancelAccountScheduledTransfers(ctx context.Context, senderID int64) error
+	CancelMemberScheduledTransfers(ctx context.Context, arg CancelMemberScheduledTransfersParams) error
+	CancelScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	CapitalizeInterestAccruals(ctx context.Context, arg CapitalizeInterestAccrualsParams) error
+	ClaimDueScheduledTransfer(ctx context.Context, now time.Time) (ScheduledTransfer, error)
+	ClaimExpiredHold(ctx context.Context, now time.Time) (Hold, error)
+	ClaimInterestAccount(ctx context.Context, today sql.NullTime) (Account, error)
+	ClaimOverdrawnAccount(ctx context.Context, arg ClaimOverdrawnAccountParams) (Account, error)
+	ClearPrimaryAccount(ctx context.Context, arg ClearPrimaryAccountParams) error
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
+	CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error)
+	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFxQuote(ctx context.Context, arg CreateFxQuoteParams) (FxQuote, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error)
+	CreateTransferBatchLine(ctx context.Context, arg CreateTransferBatchLineParams) (TransferBatchLine, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAccountMember(ctx context.Context, id int64) error
+	DeleteExpiredIdempotencyKey(ctx context.Context, arg DeleteExpiredIdempotencyKeyParams) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	ExpirePaymentRequests(ctx context.Context, now time.Time) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error)
+	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	GetAccountMemberByID(ctx context.Context, id int64) (AccountMember, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFeeTransfer(ctx context.Context, parentID sql.NullInt64) (Transfer, error)
+	GetFxQuote(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetMemberSpendUsage(ctx context.Context, arg GetMemberSpendUsageParams) (int64, error)
+	GetOwnerAccount(ctx context.Context, arg GetOwnerAccountParams) (Account, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetRefundTotals(ctx context.Context, parentID sql.NullInt64) (GetRefundTotalsRow, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStatementTotals(ctx context.Context, arg GetStatementTotalsParams) (GetStatementTotalsRow, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error)
+	GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error)
+	GetUncapitalizedInterest(ctx context.Context, arg GetUncapitalizedInterestParams) (GetUncapitalizedInterestRow, error)
+	GetUncapitalizedInterestAccount(ctx context.Context, arg GetUncapitalizedInterestAccountParams) (int64, error)
 	GetUser(ctx context.Context, id int64) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	ListAccountLedgerBalances(ctx context.Context, arg ListAccountLedgerBalancesParams) ([]ListAccountLedgerBalancesRow, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
+	ListAccountsByIDs(ctx context.Context, ids []int64) ([]Account, error)
+	ListCurrencyConversions(ctx context.Context, arg ListCurrencyConversionsParams) ([]ListCurrencyConversionsRow, error)
+	ListEffectiveExchangeRates(ctx context.Context, at time.Time) ([]ExchangeRate, error)
+	ListMemberActiveHolds(ctx context.Context, arg ListMemberActiveHoldsParams) ([]Hold, error)
+	ListOrphanEntries(ctx context.Context, arg ListOrphanEntriesParams) ([]Entry, error)
+	ListOverRefundedTransfers(ctx context.Context, arg ListOverRefundedTransfersParams) ([]ListOverRefundedTransfersRow, error)
+	ListOwnerAccountTotals(ctx context.Context, arg ListOwnerAccountTotalsParams) ([]ListOwnerAccountTotalsRow, error)
+	ListOwnerAccounts(ctx context.Context, arg ListOwnerAccountsParams) ([]Account, error)
+	ListPaymentRequests(ctx context.Context, arg ListPaymentRequestsParams) ([]PaymentRequest, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, senderID int64) ([]ScheduledTransfer, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListStatementEntriesPage(ctx context.Context, arg ListStatementEntriesPageParams) ([]ListStatementEntriesPageRow, error)
+	ListTransferBatchLines(ctx context.Context, batchID int64) ([]TransferBatchLine, error)
+	ListTransferLimitOverrides(ctx context.Context, arg ListTransferLimitOverridesParams) ([]TransferLimit, error)
+	ListUnbalancedTransfers(ctx context.Context, arg ListUnbalancedTransfersParams) ([]ListUnbalancedTransfersRow, error)
+	ListUserInvitations(ctx context.Context, userID int64) ([]AccountMember, error)
+	LockTransferUsage(ctx context.Context, arg LockTransferUsageParams) error
+	ResolvePaymentRequest(ctx context.Context, arg ResolvePaymentRequestParams) (PaymentRequest, error)
+	SaveIdempotencyKeyResponse(ctx context.Context, arg SaveIdempotencyKeyResponseParams) error
+	SetAccountInterestAccruedTo(ctx context.Context, arg SetAccountInterestAccruedToParams) (Account, error)
+	SetAccountInterestRate(ctx context.Context, arg SetAccountInterestRateParams) (Account, error)
+	SetPrimaryAccount(ctx context.Context, id int64) (Account, error)
+	SetScheduledTransferNextRun(ctx context.Context, arg SetScheduledTransferNextRunParams) (ScheduledTransfer, error)
+	UpdateAccountName(ctx context.Context, arg UpdateAccountNameParams) (Account, error)
+	UpdateAccountOverdraft(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
+	UpsertAccountTransferLimits(ctx context.Context, arg UpsertAccountTransferLimitsParams) (TransferLimit, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	UpsertUserTransferLimits(ctx context.Context, arg UpsertUserTransferLimitsParams) (TransferLimit, error)
+	UseFxQuote(ctx context.Context, id uuid.UUID) (FxQuote, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gobank/internal/fx"
+	"math/big"
+)
+
+var ErrTransferNotRefundable = errors.New("only transfers and batch lines can be refunded")
+var ErrRefundExceedsTransfer = errors.New("refunds can't exceed the amount of the transfer")
+var ErrRefundTooSmall = errors.New("refund amount is too small to be converted")
+
+type RefundTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is taken from the recipient in their currency, 0 refunds what is left of the transfer.
+	Amount int64 `json:"amount"`
+}
+
+// RefundTx sends money of a transfer back from its recipient to its sender with a new refund transfer,
+// the original transfer is never changed. Refunds of a transfer add up to at most its amount.
+// A refund in another currency is converted at the rate of the original transfer and the sender is credited
+// in proportion to the refunded part, so refunding the transfer in full gives back exactly what was sent.
+func (s *SQLStore) RefundTx(ctx context.Context, arg RefundTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	// transfers are never updated, so the original can be read before locking its accounts
+	original, err := s.GetTransfer(ctx, arg.TransferID)
+	if err != nil {
+		return result, err
+	}
+
+	if original.Kind != TransferKindTransfer && original.Kind != TransferKindBatch {
+		return result, ErrTransferNotRefundable
+	}
+
+	parentID := sql.NullInt64{Int64: original.ID, Valid: true}
+
+	err = s.execTx(ctx, []int64{original.SenderID, original.RecipientID}, func(q *Queries) error {
+		refunded, err := q.GetRefundTotals(ctx, parentID)
+		if err != nil {
+			return err
+		}
+
+		remaining := original.RecipientAmount - refunded.Amount
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+
+		if amount <= 0 || amount > remaining {
+			return ErrRefundExceedsTransfer
+		}
+
+		// the credit is derived from the refunded total, so rounding never adds up over partial refunds
+		rate := new(big.Rat).SetFrac64(original.Amount, original.RecipientAmount)
+		recipientAmount, err := fx.Convert(refunded.Amount+amount, rate)
+		if err != nil {
+			return err
+		}
+
+		recipientAmount -= refunded.RecipientAmount
+		if recipientAmount <= 0 {
+			return ErrRefundTooSmall
+		}
+
+		refund := TransferTxParams{
+			SenderID:        original.RecipientID,
+			RecipientID:     original.SenderID,
+			Amount:          amount,
+			RecipientAmount: recipientAmount,
+			Kind:            TransferKindRefund,
+			ParentID:        parentID,
+		}
+		if original.ExchangeRate.Valid {
+			refund.ExchangeRate = sql.NullString{String: fx.FormatRate(rate), Valid: true}
+		}
+
+		result, err = s.transfer(ctx, q, refund)
+		return err
+	})
+
+	return result, err
+}
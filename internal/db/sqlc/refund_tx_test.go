@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func TestRefundTx(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+
+	original, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      100,
+	})
+	require.NoError(t, err)
+
+	partial, err := testStore.RefundTx(context.Background(), RefundTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     30,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferKindRefund, partial.Transfer.Kind)
+	require.Equal(t, original.Transfer.ID, partial.Transfer.ParentID.Int64)
+	require.Equal(t, recipient.ID, partial.Transfer.SenderID)
+	require.Equal(t, sender.ID, partial.Transfer.RecipientID)
+	require.Equal(t, int64(30), partial.Transfer.Amount)
+	require.Equal(t, int64(30), partial.Transfer.RecipientAmount)
+	require.Equal(t, int64(-30), partial.SenderEntry.Amount)
+	require.Equal(t, int64(30), partial.RecipientEntry.Amount)
+
+	_, err = testStore.RefundTx(context.Background(), RefundTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     71,
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	rest, err := testStore.RefundTx(context.Background(), RefundTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), rest.Transfer.Amount)
+	require.Equal(t, sender.Balance, rest.RecipientAccount.Balance)
+	require.Equal(t, recipient.Balance, rest.SenderAccount.Balance)
+
+	_, err = testStore.RefundTx(context.Background(), RefundTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	_, err = testStore.RefundTx(context.Background(), RefundTxParams{
+		TransferID: rest.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferNotRefundable)
+
+	unchanged, err := testQueries.GetTransfer(context.Background(), original.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, original.Transfer, unchanged)
+}
+
+func TestRefundTxConverted(t *testing.T) {
+	user := createRandomUser(t)
+	sender := createRandomAccountForUser(t, user, util.EUR)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), util.USD)
+	quote := createRandomFxQuote(t, user.ID, util.EUR, util.USD, time.Now().Add(time.Minute))
+
+	original, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:        sender.ID,
+		RecipientID:     recipient.ID,
+		Amount:          100,
+		RecipientAmount: 109,
+		ExchangeRate:    sql.NullString{String: "1.0900000000", Valid: true},
+		QuoteID:         uuid.NullUUID{UUID: quote.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	var credited int64
+	for _, amount := range []int64{33, 33, 43} {
+		refund, err := testStore.RefundTx(context.Background(), RefundTxParams{
+			TransferID: original.Transfer.ID,
+			Amount:     amount,
+		})
+		require.NoError(t, err)
+		require.True(t, refund.Transfer.ExchangeRate.Valid)
+		credited += refund.Transfer.RecipientAmount
+	}
+
+	require.Equal(t, original.Transfer.Amount, credited)
+}
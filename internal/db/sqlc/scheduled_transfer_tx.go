@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gobank/internal/util"
+	"time"
+)
+
+const (
+	ScheduledTransferActive    = "active"
+	ScheduledTransferPaused    = "paused"
+	ScheduledTransferCompleted = "completed"
+	ScheduledTransferCancelled = "cancelled"
+
+	ScheduledTransferRunSucceeded = "succeeded"
+	ScheduledTransferRunFailed    = "failed"
+)
+
+type ExecuteScheduledTransferTxParams struct {
+	Now time.Time `json:"now"`
+	// MaxAttempts is the number of attempts of an occurrence before it is skipped.
+	MaxAttempts int32         `json:"max_attempts"`
+	RetryDelay  time.Duration `json:"retry_delay"`
+}
+
+type ExecuteScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+}
+
+// ExecuteScheduledTransferTx claims the active scheduled transfer that has been due the longest and makes its transfer.
+// Rows claimed by concurrent executors are skipped, sql.ErrNoRows is returned when nothing is due.
+// If the sender can't afford the transfer or it exceeds their limits, the run is recorded as failed and retried after RetryDelay,
+// after MaxAttempts the occurrence is skipped. Occurrences missed while no executor was running are skipped too.
+// Any other error of the transfer is recorded the same way in a transaction of its own, so a scheduled transfer
+// that can't be made doesn't block the ones due after it.
+func (s *SQLStore) ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error) {
+	var result ExecuteScheduledTransferTxResult
+	var claimed ScheduledTransfer
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		claimed = ScheduledTransfer{}
+
+		scheduled, err := q.ClaimDueScheduledTransfer(ctx, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		// the end date may have been moved before the pending occurrence
+		if scheduled.EndAt.Valid && scheduled.NextRunAt.Time.After(scheduled.EndAt.Time) {
+			result.ScheduledTransfer, err = q.SetScheduledTransferNextRun(ctx, SetScheduledTransferNextRunParams{
+				ID:     scheduled.ID,
+				Status: ScheduledTransferCompleted,
+			})
+			return err
+		}
+
+		err = lockAccounts(ctx, q, sortedUniqueIDs([]int64{scheduled.SenderID, scheduled.RecipientID}))
+		if err != nil {
+			return err
+		}
+		claimed = scheduled
+
+		transferResult, err := s.transfer(ctx, q, TransferTxParams{
+			SenderID:    scheduled.SenderID,
+			RecipientID: scheduled.RecipientID,
+			Amount:      scheduled.Amount,
+			CreatedBy:   scheduled.CreatedBy,
+		})
+
+		var run CreateScheduledTransferRunParams
+		var next SetScheduledTransferNextRunParams
+		switch {
+		case err == nil:
+			run = CreateScheduledTransferRunParams{
+				ScheduledTransferID: scheduled.ID,
+				Attempt:             scheduled.Attempts + 1,
+				Status:              ScheduledTransferRunSucceeded,
+				TransferID:          sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true},
+			}
+			next = SetScheduledTransferNextRunParams{
+				ID:        scheduled.ID,
+				Status:    scheduled.Status,
+				NextRunAt: nextScheduledRun(scheduled, arg.Now),
+			}
+		case errors.Is(err, ErrInsufficientFunds) || errors.Is(err, ErrLimitExceeded) ||
+			errors.Is(err, ErrAccountFrozen) || errors.Is(err, ErrAccountClosed):
+			run, next = failedScheduledRun(scheduled, arg, err)
+		default:
+			return err
+		}
+
+		return saveScheduledRun(ctx, q, run, next, &result)
+	})
+
+	if err != nil && claimed.ID != 0 && ctx.Err() == nil {
+		return s.recordScheduledTransferFailure(ctx, arg, claimed, err)
+	}
+
+	return result, err
+}
+
+// recordScheduledTransferFailure records the failed run of a scheduled transfer after its transaction was rolled back.
+// Nothing is recorded if another executor has run the occurrence in the meantime.
+func (s *SQLStore) recordScheduledTransferFailure(ctx context.Context, arg ExecuteScheduledTransferTxParams, claimed ScheduledTransfer, cause error) (ExecuteScheduledTransferTxResult, error) {
+	var result ExecuteScheduledTransferTxResult
+
+	err := s.execTx(ctx, nil, func(q *Queries) error {
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, claimed.ID)
+		if err != nil {
+			return err
+		}
+
+		if scheduled.Status != ScheduledTransferActive || scheduled.Attempts != claimed.Attempts ||
+			!scheduled.NextRunAt.Time.Equal(claimed.NextRunAt.Time) {
+			result.ScheduledTransfer = scheduled
+			return nil
+		}
+
+		run, next := failedScheduledRun(scheduled, arg, cause)
+		return saveScheduledRun(ctx, q, run, next, &result)
+	})
+
+	return result, err
+}
+
+// failedScheduledRun records a failed attempt, the occurrence is retried after RetryDelay until MaxAttempts.
+func failedScheduledRun(scheduled ScheduledTransfer, arg ExecuteScheduledTransferTxParams, cause error) (CreateScheduledTransferRunParams, SetScheduledTransferNextRunParams) {
+	run := CreateScheduledTransferRunParams{
+		ScheduledTransferID: scheduled.ID,
+		Attempt:             scheduled.Attempts + 1,
+		Status:              ScheduledTransferRunFailed,
+		Error:               sql.NullString{String: cause.Error(), Valid: true},
+	}
+
+	next := SetScheduledTransferNextRunParams{
+		ID:     scheduled.ID,
+		Status: scheduled.Status,
+	}
+	if run.Attempt < arg.MaxAttempts {
+		next.Attempts = run.Attempt
+		next.NextRunAt = sql.NullTime{Time: arg.Now.Add(arg.RetryDelay), Valid: true}
+	} else {
+		next.NextRunAt = nextScheduledRun(scheduled, arg.Now)
+	}
+
+	return run, next
+}
+
+func saveScheduledRun(ctx context.Context, q *Queries, run CreateScheduledTransferRunParams, next SetScheduledTransferNextRunParams, result *ExecuteScheduledTransferTxResult) error {
+	if !next.NextRunAt.Valid {
+		next.Status = ScheduledTransferCompleted
+	}
+
+	var err error
+	result.Run, err = q.CreateScheduledTransferRun(ctx, run)
+	if err != nil {
+		return err
+	}
+
+	result.ScheduledTransfer, err = q.SetScheduledTransferNextRun(ctx, next)
+	return err
+}
+
+func nextScheduledRun(scheduled ScheduledTransfer, now time.Time) sql.NullTime {
+	next, ok := util.NextOccurrence(scheduled.Recurrence, scheduled.StartAt, now)
+	if !ok || (scheduled.EndAt.Valid && next.After(scheduled.EndAt.Time)) {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: next, Valid: true}
+}
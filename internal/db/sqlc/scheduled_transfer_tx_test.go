@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func createRandomScheduledTransfer(t *testing.T, sender Account, recipient Account, amount int64, recurrence string, startAt time.Time) ScheduledTransfer {
+	arg := CreateScheduledTransferParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      amount,
+		Recurrence:  recurrence,
+		StartAt:     startAt,
+		NextRunAt:   sql.NullTime{Time: startAt, Valid: true},
+	}
+
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.SenderID, scheduled.SenderID)
+	require.Equal(t, arg.Amount, scheduled.Amount)
+	require.Equal(t, ScheduledTransferActive, scheduled.Status)
+	require.Zero(t, scheduled.Attempts)
+
+	return scheduled
+}
+
+// executeScheduledTransfer runs the executor until it picks the given scheduled transfer,
+// other tests may have left due rows behind.
+func executeScheduledTransfer(t *testing.T, id int64, arg ExecuteScheduledTransferTxParams) ExecuteScheduledTransferTxResult {
+	for {
+		result, err := testStore.ExecuteScheduledTransferTx(context.Background(), arg)
+		require.NoError(t, err)
+		if result.ScheduledTransfer.ID == id {
+			return result
+		}
+	}
+}
+
+func TestExecuteScheduledTransferTx(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+	startAt := time.Now().Add(-time.Minute)
+	scheduled := createRandomScheduledTransfer(t, sender, recipient, 10, util.Monthly, startAt)
+
+	arg := ExecuteScheduledTransferTxParams{
+		Now:         time.Now(),
+		MaxAttempts: 3,
+		RetryDelay:  time.Hour,
+	}
+
+	result := executeScheduledTransfer(t, scheduled.ID, arg)
+	require.Equal(t, ScheduledTransferRunSucceeded, result.Run.Status)
+	require.True(t, result.Run.TransferID.Valid)
+	require.Equal(t, ScheduledTransferActive, result.ScheduledTransfer.Status)
+	require.True(t, result.ScheduledTransfer.NextRunAt.Valid)
+	require.True(t, result.ScheduledTransfer.NextRunAt.Time.After(arg.Now))
+
+	transfer, err := testQueries.GetTransfer(context.Background(), result.Run.TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, sender.ID, transfer.SenderID)
+	require.Equal(t, recipient.ID, transfer.RecipientID)
+	require.Equal(t, scheduled.Amount, transfer.Amount)
+
+	updated, err := testQueries.GetAccount(context.Background(), sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, sender.Balance-scheduled.Amount, updated.Balance)
+}
+
+func TestExecuteScheduledTransferTxInsufficientFunds(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+	scheduled := createRandomScheduledTransfer(t, sender, recipient, sender.Balance+1, util.Once, time.Now().Add(-time.Minute))
+
+	arg := ExecuteScheduledTransferTxParams{
+		MaxAttempts: 2,
+		RetryDelay:  time.Minute,
+	}
+
+	arg.Now = time.Now()
+	result := executeScheduledTransfer(t, scheduled.ID, arg)
+	require.Equal(t, ScheduledTransferRunFailed, result.Run.Status)
+	require.Equal(t, int32(1), result.Run.Attempt)
+	require.Equal(t, ErrInsufficientFunds.Error(), result.Run.Error.String)
+	require.Equal(t, int32(1), result.ScheduledTransfer.Attempts)
+	require.Equal(t, ScheduledTransferActive, result.ScheduledTransfer.Status)
+	require.WithinDuration(t, arg.Now.Add(arg.RetryDelay), result.ScheduledTransfer.NextRunAt.Time, time.Second)
+
+	arg.Now = result.ScheduledTransfer.NextRunAt.Time
+	result = executeScheduledTransfer(t, scheduled.ID, arg)
+	require.Equal(t, ScheduledTransferRunFailed, result.Run.Status)
+	require.Equal(t, int32(2), result.Run.Attempt)
+	require.Equal(t, ScheduledTransferCompleted, result.ScheduledTransfer.Status)
+	require.False(t, result.ScheduledTransfer.NextRunAt.Valid)
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	unchanged, err := testQueries.GetAccount(context.Background(), sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, sender.Balance, unchanged.Balance)
+}
+
+func TestRecordScheduledTransferFailure(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+	scheduled := createRandomScheduledTransfer(t, sender, recipient, 10, util.Monthly, time.Now().Add(-time.Minute))
+
+	arg := ExecuteScheduledTransferTxParams{
+		Now:         time.Now(),
+		MaxAttempts: 2,
+		RetryDelay:  time.Minute,
+	}
+
+	// an error TransferTx doesn't expect, the transaction of the transfer was rolled back
+	store := testStore.(*SQLStore)
+	cause := errors.New("no EUR fees account")
+	result, err := store.recordScheduledTransferFailure(context.Background(), arg, scheduled, cause)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferRunFailed, result.Run.Status)
+	require.Equal(t, cause.Error(), result.Run.Error.String)
+	require.Equal(t, int32(1), result.ScheduledTransfer.Attempts)
+	require.WithinDuration(t, arg.Now.Add(arg.RetryDelay), result.ScheduledTransfer.NextRunAt.Time, time.Second)
+
+	// the occurrence was moved on by the first record, the claim is stale
+	result, err = store.recordScheduledTransferFailure(context.Background(), arg, scheduled, cause)
+	require.NoError(t, err)
+	require.Zero(t, result.Run.ID)
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+}
+
+func TestCancelScheduledTransfer(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+	scheduled := createRandomScheduledTransfer(t, sender, recipient, 10, util.Weekly, time.Now().Add(time.Hour))
+
+	cancelled, err := testQueries.CancelScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferCancelled, cancelled.Status)
+	require.False(t, cancelled.NextRunAt.Valid)
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type StatementTxParams struct {
+	AccountID int64     `json:"account_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+type StatementTxResult struct {
+	OpeningBalance int64                     `json:"opening_balance"`
+	ClosingBalance int64                     `json:"closing_balance"`
+	TotalIn        int64                     `json:"total_in"`
+	TotalOut       int64                     `json:"total_out"`
+	Entries        []ListStatementEntriesRow `json:"entries"`
+}
+
+// StatementTx reads the account entries in [From, To) with the balance after each of them.
+// The opening balance is derived from the current balance, so accounts created with an initial balance add up too.
+func (s *SQLStore) StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error) {
+	var result StatementTxResult
+
+	err := s.readTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.OpeningBalance, err = q.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{
+			At:        arg.From,
+			AccountID: arg.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entries, err = q.ListStatementEntries(ctx, ListStatementEntriesParams{
+			OpeningBalance: result.OpeningBalance,
+			AccountID:      arg.AccountID,
+			FromTime:       arg.From,
+			ToTime:         arg.To,
+		})
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ClosingBalance = result.OpeningBalance
+	for _, entry := range result.Entries {
+		if entry.Amount > 0 {
+			result.TotalIn += entry.Amount
+		} else {
+			result.TotalOut -= entry.Amount
+		}
+		result.ClosingBalance = entry.RunningBalance
+	}
+
+	return result, nil
+}
+
+const statementExportPageSize = 500
+
+type StatementSummary struct {
+	OpeningBalance int64 `json:"opening_balance"`
+	ClosingBalance int64 `json:"closing_balance"`
+	TotalIn        int64 `json:"total_in"`
+	TotalOut       int64 `json:"total_out"`
+	CreditCount    int64 `json:"credit_count"`
+	DebitCount     int64 `json:"debit_count"`
+}
+
+type StatementLine struct {
+	ListStatementEntriesPageRow
+	RunningBalance int64 `json:"running_balance"`
+}
+
+// ExportStatementTx reads the same statement as StatementTx without loading all entries at once.
+// The summary is passed to begin before any entry, then entries are read page by page and passed to each.
+func (s *SQLStore) ExportStatementTx(ctx context.Context, arg StatementTxParams, begin func(StatementSummary) error, each func(StatementLine) error) error {
+	return s.readTx(ctx, func(q *Queries) error {
+		opening, err := q.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{
+			At:        arg.From,
+			AccountID: arg.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		totals, err := q.GetStatementTotals(ctx, GetStatementTotalsParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.From,
+			ToTime:    arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = begin(StatementSummary{
+			OpeningBalance: opening,
+			ClosingBalance: opening + totals.TotalIn - totals.TotalOut,
+			TotalIn:        totals.TotalIn,
+			TotalOut:       totals.TotalOut,
+			CreditCount:    totals.CreditCount,
+			DebitCount:     totals.DebitCount,
+		})
+		if err != nil {
+			return err
+		}
+
+		page := ListStatementEntriesPageParams{
+			AccountID:      arg.AccountID,
+			FromTime:       arg.From,
+			ToTime:         arg.To,
+			AfterCreatedAt: arg.From,
+			PageSize:       statementExportPageSize,
+		}
+		balance := opening
+
+		for {
+			entries, err := q.ListStatementEntriesPage(ctx, page)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				balance += entry.Amount
+				if err := each(StatementLine{ListStatementEntriesPageRow: entry, RunningBalance: balance}); err != nil {
+					return err
+				}
+			}
+
+			if len(entries) < statementExportPageSize {
+				return nil
+			}
+
+			last := entries[len(entries)-1]
+			page.AfterCreatedAt = last.CreatedAt
+			page.AfterID = last.ID
+		}
+	})
+}
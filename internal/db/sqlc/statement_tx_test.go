@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStatementTx(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t)
+	from := time.Now().Add(-time.Second)
+
+	amounts := []int64{10, -4, 7}
+	for _, amount := range amounts {
+		arg := TransferTxParams{
+			SenderID:    account2.ID,
+			RecipientID: account1.ID,
+			Amount:      amount,
+		}
+		if amount < 0 {
+			arg = TransferTxParams{
+				SenderID:    account1.ID,
+				RecipientID: account2.ID,
+				Amount:      -amount,
+			}
+		}
+
+		_, err := testStore.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	statement, err := testStore.StatementTx(context.Background(), StatementTxParams{
+		AccountID: account1.ID,
+		From:      from,
+		To:        time.Now().Add(time.Second),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance, statement.OpeningBalance)
+	require.Equal(t, account1.Balance+13, statement.ClosingBalance)
+	require.Equal(t, int64(17), statement.TotalIn)
+	require.Equal(t, int64(4), statement.TotalOut)
+	require.Len(t, statement.Entries, 3)
+
+	balance := statement.OpeningBalance
+	for i, entry := range statement.Entries {
+		balance += amounts[i]
+		require.Equal(t, amounts[i], entry.Amount)
+		require.Equal(t, balance, entry.RunningBalance)
+		require.Equal(t, account2.ID, entry.CounterpartyID)
+	}
+}
+
+func TestExportStatementTx(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t)
+	from := time.Now().Add(-time.Second)
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			SenderID:    account2.ID,
+			RecipientID: account1.ID,
+			Amount:      int64(i + 1),
+		})
+		require.NoError(t, err)
+	}
+
+	arg := StatementTxParams{
+		AccountID: account1.ID,
+		From:      from,
+		To:        time.Now().Add(time.Second),
+	}
+
+	statement, err := testStore.StatementTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	var summary StatementSummary
+	var lines []StatementLine
+
+	err = testStore.ExportStatementTx(context.Background(), arg, func(s StatementSummary) error {
+		require.Empty(t, lines)
+		summary = s
+		return nil
+	}, func(line StatementLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, statement.OpeningBalance, summary.OpeningBalance)
+	require.Equal(t, statement.ClosingBalance, summary.ClosingBalance)
+	require.Equal(t, statement.TotalIn, summary.TotalIn)
+	require.Equal(t, statement.TotalOut, summary.TotalOut)
+	require.Equal(t, int64(n), summary.CreditCount)
+	require.Zero(t, summary.DebitCount)
+
+	require.Len(t, lines, n)
+	for i, line := range lines {
+		require.Equal(t, statement.Entries[i].ID, line.ID)
+		require.Equal(t, statement.Entries[i].RunningBalance, line.RunningBalance)
+		require.Equal(t, account2.ID, line.CounterpartyID)
+	}
+}
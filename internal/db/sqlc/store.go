@@ -1,19 +1,436 @@
 package db
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gobank/internal/fees"
+	"gobank/internal/util"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+var ErrQuoteUnavailable = errors.New("fx quote is expired or was already used")
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// AccountError is returned by the transactions jobs run on one account at a time when that account failed,
+// the job can skip the account and go on with the others.
+type AccountError struct {
+	AccountID int64
+	Err       error
+}
+
+func (e *AccountError) Error() string {
+	return fmt.Sprintf("account %d: %v", e.AccountID, e.Err)
+}
+
+func (e *AccountError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	TransferKindTransfer = "transfer"
+	TransferKindRefund   = "refund"
+	// TransferKindOverdraftFee transfers are posted by the system and may overdraw the account further.
+	TransferKindOverdraftFee = "overdraft_fee"
+	TransferKindDeposit      = "deposit"
+	TransferKindWithdrawal   = "withdrawal"
+	// TransferKindFee transfers charge the fees of their parent transfer.
+	TransferKindFee = "fee"
+	// TransferKindInterest transfers pay the capitalized interest from the system interest account.
+	TransferKindInterest = "interest"
+	// TransferKindBatch transfers are the lines of a transfer batch.
+	TransferKindBatch = "batch"
+	// TransferKindMove transfers move money between accounts of one owner in one currency, they are free and unlimited.
+	TransferKindMove = "move"
+)
 
 type Store interface {
 	Querier
+	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ImportExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) ([]ExchangeRate, error)
+	RefundTx(ctx context.Context, arg RefundTxParams) (TransferTxResult, error)
+	AuthorizeHoldTx(ctx context.Context, arg CreateHoldParams) (HoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (HoldTxResult, error)
+	VoidHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error)
+	ExpireHoldTx(ctx context.Context, now time.Time) (HoldTxResult, error)
+	AccountLimitsTx(ctx context.Context, account Account) (AccountLimitsTxResult, error)
+	UpdateOverdraftTx(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error)
+	ChargeOverdraftFeeTx(ctx context.Context, arg ChargeOverdraftFeeTxParams) (TransferTxResult, error)
+	DepositTx(ctx context.Context, arg FundingTxParams) (TransferTxResult, error)
+	WithdrawalTx(ctx context.Context, arg FundingTxParams) (TransferTxResult, error)
+	ReverseWithdrawalTx(ctx context.Context, transferID int64) (TransferTxResult, error)
+	PreviewTransferFees(sender Account, arg TransferTxParams) TransferFees
+	AccrueInterestTx(ctx context.Context, now time.Time) (InterestAccrual, error)
+	CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (CapitalizeInterestTxResult, error)
+	GetAccruedInterest(ctx context.Context, accountID int64) (AccruedInterest, error)
+	BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error)
+	ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error)
+	UpdateAccountTx(ctx context.Context, arg UpdateAccountTxParams) (Account, error)
+	RemoveAccountMemberTx(ctx context.Context, member AccountMember) error
+	AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (PaymentRequestTxResult, error)
+	DeclinePaymentRequestTx(ctx context.Context, requestID int64) (PaymentRequest, error)
+	CancelPaymentRequestTx(ctx context.Context, requestID int64) (PaymentRequest, error)
+	ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error)
+	StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error)
+	ExportStatementTx(ctx context.Context, arg StatementTxParams, begin func(StatementSummary) error, each func(StatementLine) error) error
 }
 
 type SQLStore struct {
 	*Queries
-	db *sql.DB
+	db     *sql.DB
+	config util.Config
+	fees   *fees.Engine
 }
 
-func NewSQLStore(db *sql.DB) Store {
+func NewSQLStore(db *sql.DB, config util.Config) Store {
 	return &SQLStore{
 		db:      db,
+		config:  config,
+		fees:    fees.New(config.FeeRules),
 		Queries: New(db),
 	}
 }
+
+const (
+	deadlockDetectedCode     = "40P01"
+	serializationFailureCode = "40001"
+
+	txRetryBaseDelay = 10 * time.Millisecond
+	txRetryMaxDelay  = time.Second
+)
+
+// execTx runs fn inside a database transaction.
+// The accounts in lockIDs are locked in ascending ID order before fn is called, so concurrent
+// transactions over the same accounts queue up instead of deadlocking. Deadlocks and serialization
+// failures are still retried with a jittered backoff, up to config.TxMaxRetries times.
+func (s *SQLStore) execTx(ctx context.Context, lockIDs []int64, fn func(*Queries) error) error {
+	ids := sortedUniqueIDs(lockIDs)
+
+	for attempt := 0; ; attempt++ {
+		err := s.runTx(ctx, ids, fn)
+		if !isRetryableTxError(err) || attempt >= s.config.TxMaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(txRetryBackoff(attempt)):
+		}
+	}
+}
+
+func (s *SQLStore) runTx(ctx context.Context, lockIDs []int64, fn func(*Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = lockAccounts(ctx, q, lockIDs)
+	if err == nil {
+		err = fn(q)
+	}
+
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// readTx runs fn in a read-only repeatable read transaction, so every query in it sees the same snapshot.
+func (s *SQLStore) readTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(New(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockAccounts(ctx context.Context, q *Queries, ids []int64) error {
+	for _, id := range ids {
+		if _, err := q.GetAccountForUpdate(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedUniqueIDs(ids []int64) []int64 {
+	res := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == deadlockDetectedCode || pqErr.Code == serializationFailureCode
+	}
+	return false
+}
+
+// txRetryBackoff doubles the delay on every attempt and picks a random point
+// in its upper half, so transactions that collided once don't collide again.
+func txRetryBackoff(attempt int) time.Duration {
+	delay := txRetryMaxDelay
+	if attempt < 10 {
+		delay = txRetryBaseDelay << attempt
+	}
+	if delay > txRetryMaxDelay {
+		delay = txRetryMaxDelay
+	}
+
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+type TransferTxParams struct {
+	SenderID    int64 `json:"sender_id"`
+	RecipientID int64 `json:"recipient_id"`
+	Amount      int64 `json:"amount"`
+	// RecipientAmount is credited to the recipient in their currency, defaults to Amount.
+	RecipientAmount int64          `json:"recipient_amount"`
+	ExchangeRate    sql.NullString `json:"exchange_rate"`
+	// QuoteID is marked as used by the transfer, a quote can't be used twice.
+	QuoteID uuid.NullUUID `json:"quote_id"`
+	// Kind defaults to TransferKindTransfer.
+	Kind        string         `json:"kind"`
+	ParentID    sql.NullInt64  `json:"parent_id"`
+	ExternalRef sql.NullString `json:"external_ref"`
+	BatchID     sql.NullInt64  `json:"batch_id"`
+	Memo        string         `json:"memo"`
+	Reference   string         `json:"reference"`
+	// Metadata is a JSON object, an empty one when nil.
+	Metadata json.RawMessage `json:"metadata"`
+	// CreatedBy is the account member who makes the transfer, their daily spend limit applies to it.
+	CreatedBy sql.NullInt64 `json:"created_by"`
+}
+
+type TransferTxResult struct {
+	Transfer Transfer `json:"transfer"`
+	// SenderAccount is updated after the fees are charged.
+	SenderAccount    Account `json:"sender_account"`
+	RecipientAccount Account `json:"recipient_account"`
+	SenderEntry      Entry   `json:"sender_entry"`
+	RecipientEntry   Entry   `json:"recipient_entry"`
+	// Fees are charged to the sender with a single FeeTransfer, nil when there is no fee.
+	Fees        []fees.Fee `json:"fees"`
+	FeeTransfer *Transfer  `json:"fee_transfer"`
+}
+
+// TransferTx moves money from the sender account to the recipient account.
+// The transfer record, both entries and both balance updates are written in a single transaction.
+// ErrInsufficientFunds is returned if the sender overdraft policy doesn't allow the amount.
+func (s *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := s.execTx(ctx, []int64{arg.SenderID, arg.RecipientID}, func(q *Queries) error {
+		var err error
+		result, err = s.transfer(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// transfer writes a transfer and its fees inside a transaction that already holds the locks of both accounts.
+// Transfers and withdrawals then take the usage lock of the sender owner, and the row of the fees account is
+// locked last, implicitly, by the balance update that posts the fee. Nothing is written when the sender can't
+// afford the amount and fees, the transfer exceeds the sender limits or the spend limit of the member who makes it,
+// or the status of an account doesn't allow it, so the transaction can go on after ErrInsufficientFunds,
+// ErrLimitExceeded, ErrAccountFrozen and ErrAccountClosed.
+// A plain transfer between two accounts of the same owner and currency is posted as a free move.
+func (s *SQLStore) transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	if arg.RecipientAmount == 0 {
+		arg.RecipientAmount = arg.Amount
+	}
+
+	if arg.Kind == "" {
+		arg.Kind = TransferKindTransfer
+	}
+
+	sender, err := q.GetAccount(ctx, arg.SenderID)
+	if err != nil {
+		return result, err
+	}
+
+	recipient, err := q.GetAccount(ctx, arg.RecipientID)
+	if err != nil {
+		return result, err
+	}
+
+	if arg.Kind == TransferKindTransfer {
+		arg.Kind = TransferKindBetween(sender, recipient)
+	}
+
+	if err := s.checkAccountStatus(sender, recipient, arg.Kind); err != nil {
+		return result, err
+	}
+
+	fee := s.PreviewTransferFees(sender, arg)
+
+	if arg.Kind != TransferKindOverdraftFee && !sender.canDebit(arg.Amount+fee.Total) {
+		return result, ErrInsufficientFunds
+	}
+
+	// withdrawals take money out like transfers and share their limits, refunds are bounded by the refunded
+	// transfer, they don't count against the limits, batches are checked as a whole before their lines are written
+	if arg.Kind == TransferKindTransfer || arg.Kind == TransferKindWithdrawal {
+		if err := lockUsage(ctx, q, sender); err != nil {
+			return result, err
+		}
+
+		limits, err := s.accountLimits(ctx, q, sender, time.Now())
+		if err != nil {
+			return result, err
+		}
+
+		if err := checkLimits(limits.Limits, limits.Usage, arg.Amount); err != nil {
+			return result, err
+		}
+	}
+
+	if arg.Kind != TransferKindBatch {
+		if err := checkSpendLimit(ctx, q, sender, arg.CreatedBy, arg.Amount+fee.Total, time.Now()); err != nil {
+			return result, err
+		}
+	}
+
+	if arg.QuoteID.Valid {
+		_, err = q.UseFxQuote(ctx, arg.QuoteID.UUID)
+		if err == sql.ErrNoRows {
+			return result, ErrQuoteUnavailable
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result, err = postTransfer(ctx, q, arg)
+	if err != nil || fee.Total == 0 {
+		return result, err
+	}
+
+	feesAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Kind:     AccountKindFees,
+		Currency: sender.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return result, fmt.Errorf("no %s fees account", sender.Currency)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	feeResult, err := postTransfer(ctx, q, TransferTxParams{
+		SenderID:        arg.SenderID,
+		RecipientID:     feesAccount.ID,
+		Amount:          fee.Total,
+		RecipientAmount: fee.Total,
+		Kind:            TransferKindFee,
+		ParentID:        sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		CreatedBy:       arg.CreatedBy,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Fees = fee.Fees
+	result.FeeTransfer = &feeResult.Transfer
+	result.SenderAccount = feeResult.SenderAccount
+	return result, nil
+}
+
+// postTransfer writes the transfer record, both entries and both balance updates, without any check.
+func postTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+
+	metadata := arg.Metadata
+	if metadata == nil {
+		metadata = json.RawMessage("{}")
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		SenderID:        arg.SenderID,
+		RecipientID:     arg.RecipientID,
+		Amount:          arg.Amount,
+		RecipientAmount: arg.RecipientAmount,
+		ExchangeRate:    arg.ExchangeRate,
+		QuoteID:         arg.QuoteID,
+		Kind:            arg.Kind,
+		ParentID:        arg.ParentID,
+		ExternalRef:     arg.ExternalRef,
+		BatchID:         arg.BatchID,
+		Memo:            arg.Memo,
+		Reference:       arg.Reference,
+		Metadata:        metadata,
+		CreatedBy:       arg.CreatedBy,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
+	result.SenderEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.SenderID,
+		Amount:     -arg.Amount,
+		TransferID: transferID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.RecipientEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.RecipientID,
+		Amount:     arg.RecipientAmount,
+		TransferID: transferID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.SenderAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     arg.SenderID,
+		Amount: -arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.RecipientAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     arg.RecipientID,
+		Amount: arg.RecipientAmount,
+	})
+	return result, err
+}
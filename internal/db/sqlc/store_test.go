@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"testing"
+	"time"
+)
+
+func createRandomAccountPair(t *testing.T) (Account, Account) {
+	currency := util.RandomCurrency()
+	sender := createRandomAccountForUser(t, createRandomUser(t), currency)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), currency)
+	return sender, recipient
+}
+
+func TestTransferTx(t *testing.T) {
+	store := testStore
+	sender, recipient := createRandomAccountPair(t)
+	amount := int64(10)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      amount,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+
+	transfer := result.Transfer
+	require.NotZero(t, transfer.ID)
+	require.Equal(t, sender.ID, transfer.SenderID)
+	require.Equal(t, recipient.ID, transfer.RecipientID)
+	require.Equal(t, amount, transfer.Amount)
+	require.NotZero(t, transfer.CreatedAt)
+
+	_, err = store.GetTransfer(context.Background(), transfer.ID)
+	require.NoError(t, err)
+
+	senderEntry := result.SenderEntry
+	require.Equal(t, sender.ID, senderEntry.AccountID)
+	require.Equal(t, -amount, senderEntry.Amount)
+	require.Equal(t, transfer.ID, senderEntry.TransferID.Int64)
+
+	recipientEntry := result.RecipientEntry
+	require.Equal(t, recipient.ID, recipientEntry.AccountID)
+	require.Equal(t, amount, recipientEntry.Amount)
+	require.Equal(t, transfer.ID, recipientEntry.TransferID.Int64)
+
+	_, err = store.GetEntry(context.Background(), senderEntry.ID)
+	require.NoError(t, err)
+
+	_, err = store.GetEntry(context.Background(), recipientEntry.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, sender.Balance-amount, result.SenderAccount.Balance)
+	require.Equal(t, recipient.Balance+amount, result.RecipientAccount.Balance)
+}
+
+func TestTransferTxBothWays(t *testing.T) {
+	store := testStore
+	account1, account2 := createRandomAccountPair(t)
+
+	n := 20
+	amount := int64(10)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		senderID, recipientID := account1.ID, account2.ID
+		if i%2 == 1 {
+			senderID, recipientID = account2.ID, account1.ID
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				SenderID:    senderID,
+				RecipientID: recipientID,
+				Amount:      amount,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updated1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updated2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance, updated1.Balance)
+	require.Equal(t, account2.Balance, updated2.Balance)
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      sender.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	unchanged, err := testStore.GetAccount(context.Background(), sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, sender.Balance, unchanged.Balance)
+}
+
+func TestSortedUniqueIDs(t *testing.T) {
+	require.Equal(t, []int64{1, 2, 5}, sortedUniqueIDs([]int64{5, 1, 2, 5, 1}))
+	require.Empty(t, sortedUniqueIDs(nil))
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	require.True(t, isRetryableTxError(&pq.Error{Code: deadlockDetectedCode}))
+	require.True(t, isRetryableTxError(&pq.Error{Code: serializationFailureCode}))
+	require.True(t, isRetryableTxError(fmt.Errorf("tx err: %w", &pq.Error{Code: deadlockDetectedCode})))
+
+	require.False(t, isRetryableTxError(nil))
+	require.False(t, isRetryableTxError(&pq.Error{Code: "23505"}))
+	require.False(t, isRetryableTxError(fmt.Errorf("some error")))
+}
+
+func TestTxRetryBackoff(t *testing.T) {
+	for attempt := 0; attempt < 20; attempt++ {
+		delay := txRetryBackoff(attempt)
+		require.True(t, delay >= txRetryBaseDelay/2)
+		require.True(t, delay <= txRetryMaxDelay)
+	}
+}
+
+func TestTransferTxWithQuote(t *testing.T) {
+	user := createRandomUser(t)
+	sender := createRandomAccountForUser(t, user, util.EUR)
+	recipient := createRandomAccountForUser(t, createRandomUser(t), util.USD)
+	quote := createRandomFxQuote(t, user.ID, util.EUR, util.USD, time.Now().Add(time.Minute))
+
+	arg := TransferTxParams{
+		SenderID:        sender.ID,
+		RecipientID:     recipient.ID,
+		Amount:          10,
+		RecipientAmount: 5,
+		ExchangeRate:    sql.NullString{String: quote.Rate, Valid: true},
+		QuoteID:         uuid.NullUUID{UUID: quote.ID, Valid: true},
+	}
+
+	result, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.Amount, result.Transfer.Amount)
+	require.Equal(t, arg.RecipientAmount, result.Transfer.RecipientAmount)
+	require.Equal(t, arg.ExchangeRate, result.Transfer.ExchangeRate)
+	require.Equal(t, arg.QuoteID, result.Transfer.QuoteID)
+
+	require.Equal(t, -arg.Amount, result.SenderEntry.Amount)
+	require.Equal(t, arg.RecipientAmount, result.RecipientEntry.Amount)
+	require.Equal(t, sender.Balance-arg.Amount, result.SenderAccount.Balance)
+	require.Equal(t, recipient.Balance+arg.RecipientAmount, result.RecipientAccount.Balance)
+
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrQuoteUnavailable)
+}
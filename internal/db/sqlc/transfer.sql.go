@@ -7,10 +7,169 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createTransfer = `-- name: CreateTransfer :one
+INSERT INTO transfers
+(
+    sender_id,
+    recipient_id,
+    amount,
+    recipient_amount,
+    exchange_rate,
+    quote_id,
+    kind,
+    parent_id,
+    external_ref,
+    batch_id,
+    memo,
+    reference,
+    metadata,
+    created_by
 )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, sender_id, recipient_id, amount, created_at, recipient_amount, exchange_rate, quote_id, kind, parent_id, external_ref, batch_id, memo, reference, metadata, created_by
+`
+
+type CreateTransferParams struct {
+	SenderID        int64           `json:"sender_id"`
+	RecipientID     int64           `json:"recipient_id"`
+	Amount          int64           `json:"amount"`
+	RecipientAmount int64           `json:"recipient_amount"`
+	ExchangeRate    sql.NullString  `json:"exchange_rate"`
+	QuoteID         uuid.NullUUID   `json:"quote_id"`
+	Kind            string          `json:"kind"`
+	ParentID        sql.NullInt64   `json:"parent_id"`
+	ExternalRef     sql.NullString  `json:"external_ref"`
+	BatchID         sql.NullInt64   `json:"batch_id"`
+	Memo            string          `json:"memo"`
+	Reference       string          `json:"reference"`
+	Metadata        json.RawMessage `json:"metadata"`
+	CreatedBy       sql.NullInt64   `json:"created_by"`
+}
+
+func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.SenderID,
+		arg.RecipientID,
+		arg.Amount,
+		arg.RecipientAmount,
+		arg.ExchangeRate,
+		arg.QuoteID,
+		arg.Kind,
+		arg.ParentID,
+		arg.ExternalRef,
+		arg.BatchID,
+		arg.Memo,
+		arg.Reference,
+		arg.Metadata,
+		arg.CreatedBy,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.SenderID,
+		&i.RecipientID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.RecipientAmount,
+		&i.ExchangeRate,
+		&i.QuoteID,
+		&i.Kind,
+		&i.ParentID,
+		&i.ExternalRef,
+		&i.BatchID,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const getFeeTransfer = `-- name: GetFeeTransfer :one
+SELECT id, sender_id, recipient_id, amount, created_at, recipient_amount, exchange_rate, quote_id, kind, parent_id, external_ref, batch_id, memo, reference, metadata, created_by FROM transfers
+WHERE parent_id = $1 AND kind = 'fee'
+LIMIT 1
+`
+
+func (q *Queries) GetFeeTransfer(ctx context.Context, parentID sql.NullInt64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getFeeTransfer, parentID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.SenderID,
+		&i.RecipientID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.RecipientAmount,
+		&i.ExchangeRate,
+		&i.QuoteID,
+		&i.Kind,
+		&i.ParentID,
+		&i.ExternalRef,
+		&i.BatchID,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const getMemberSpendUsage = `-- name: GetMemberSpendUsage :one
+SELECT ((
+    SELECT COALESCE(SUM(amount), 0) FROM transfers
+    WHERE sender_id = $1 AND created_by = $2 AND created_at >= $3
+) + (
+    SELECT COALESCE(SUM(CASE WHEN status = 'captured' THEN captured_amount ELSE amount END), 0) FROM holds
+    WHERE account_id = $1 AND created_by = $2 AND created_at >= $3
+      AND status IN ('active', 'captured')
+))::bigint AS amount
+`
+
+type GetMemberSpendUsageParams struct {
+	AccountID int64         `json:"account_id"`
+	UserID    sql.NullInt64 `json:"user_id"`
+	DayStart  time.Time     `json:"day_start"`
+}
+
+func (q *Queries) GetMemberSpendUsage(ctx context.Context, arg GetMemberSpendUsageParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getMemberSpendUsage, arg.AccountID, arg.UserID, arg.DayStart)
+	var amount int64
+	err := row.Scan(&amount)
+	return amount, err
+}
+
+const getRefundTotals = `-- name: GetRefundTotals :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount,
+       COALESCE(SUM(recipient_amount), 0)::bigint AS recipient_amount
+FROM transfers
+WHERE parent_id = $1 AND kind = 'refund'
+`
+
+type GetRefundTotalsRow struct {
+	Amount          int64 `json:"amount"`
+	RecipientAmount int64 `json:"recipient_amount"`
+}
+
+func (q *Queries) GetRefundTotals(ctx context.Context, parentID sql.NullInt64) (GetRefundTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getRefundTotals, parentID)
+	var i GetRefundTotalsRow
+	err := row.Scan(
+		&i.Amount,
+		&i.RecipientAmount,
+	)
+	return i, err
+}
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, sender_id, recipient_id, amount, created_at FROM transfers
+SELECT id, sender_id, recipient_id, amount, created_at, recipient_amount, exchange_rate, quote_id, kind, parent_id, external_ref, batch_id, memo, reference, metadata, created_by FROM transfers
 WHERE id = $1
 LIMIT 1
 `
@@ -24,6 +183,164 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.RecipientID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.RecipientAmount,
+		&i.ExchangeRate,
+		&i.QuoteID,
+		&i.Kind,
+		&i.ParentID,
+		&i.ExternalRef,
+		&i.BatchID,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.CreatedBy,
 	)
 	return i, err
 }
+
+const getTransferUsage = `-- name: GetTransferUsage :one
+SELECT COALESCE(SUM(amount) FILTER (WHERE created_at >= $1), 0)::bigint AS daily_amount,
+       (COUNT(*) FILTER (WHERE created_at >= $1 AND batch_id IS NULL) +
+        COUNT(DISTINCT batch_id) FILTER (WHERE created_at >= $1))::bigint AS daily_count,
+       COALESCE(SUM(amount), 0)::bigint AS monthly_amount,
+       (COUNT(*) FILTER (WHERE batch_id IS NULL) + COUNT(DISTINCT batch_id))::bigint AS monthly_count
+FROM transfers
+WHERE sender_id IN (
+    SELECT id FROM accounts
+    WHERE id = $2
+       OR (owner_id = $3 AND currency = $4 AND kind = 'customer')
+)
+  AND kind IN ('transfer', 'batch', 'withdrawal')
+  AND created_at >= $5
+`
+
+type GetTransferUsageParams struct {
+	DayStart   time.Time     `json:"day_start"`
+	AccountID  int64         `json:"account_id"`
+	OwnerID    sql.NullInt64 `json:"owner_id"`
+	Currency   string        `json:"currency"`
+	MonthStart time.Time     `json:"month_start"`
+}
+
+type GetTransferUsageRow struct {
+	DailyAmount   int64 `json:"daily_amount"`
+	DailyCount    int64 `json:"daily_count"`
+	MonthlyAmount int64 `json:"monthly_amount"`
+	MonthlyCount  int64 `json:"monthly_count"`
+}
+
+func (q *Queries) GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferUsage,
+		arg.DayStart,
+		arg.AccountID,
+		arg.OwnerID,
+		arg.Currency,
+		arg.MonthStart,
+	)
+	var i GetTransferUsageRow
+	err := row.Scan(
+		&i.DailyAmount,
+		&i.DailyCount,
+		&i.MonthlyAmount,
+		&i.MonthlyCount,
+	)
+	return i, err
+}
+
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT id, sender_id, recipient_id, amount, created_at, recipient_amount, exchange_rate, quote_id, kind, parent_id, external_ref, batch_id, memo, reference, metadata, created_by FROM (
+    SELECT id, sender_id, recipient_id, amount, created_at, recipient_amount, exchange_rate, quote_id, kind, parent_id, external_ref, batch_id, memo, reference, metadata, created_by FROM transfers
+    WHERE sender_id = $1 AND $2::boolean
+    UNION ALL
+    SELECT id, sender_id, recipient_id, amount, created_at, recipient_amount, exchange_rate, quote_id, kind, parent_id, external_ref, batch_id, memo, reference, metadata, created_by FROM transfers
+    WHERE recipient_id = $1 AND $3::boolean
+) AS t
+WHERE ($4::bigint IS NULL OR CASE WHEN t.sender_id = $1 THEN t.amount ELSE t.recipient_amount END >= $4)
+  AND ($5::bigint IS NULL OR CASE WHEN t.sender_id = $1 THEN t.amount ELSE t.recipient_amount END <= $5)
+  AND ($6::timestamptz IS NULL OR t.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR t.created_at < $7)
+  AND ($8::text IS NULL OR (to_tsvector('simple', t.memo || ' ' || t.reference) || jsonb_to_tsvector('simple', t.metadata, '["string"]')) @@ websearch_to_tsquery('simple', $8))
+  AND ($9::timestamptz IS NULL OR (t.created_at, t.id) < ($9, $10::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $11
+`
+
+type ListAccountTransfersParams struct {
+	AccountID       int64          `json:"account_id"`
+	IncludeSent     bool           `json:"include_sent"`
+	IncludeReceived bool           `json:"include_received"`
+	MinAmount       sql.NullInt64  `json:"min_amount"`
+	MaxAmount       sql.NullInt64  `json:"max_amount"`
+	FromTime        sql.NullTime   `json:"from_time"`
+	ToTime          sql.NullTime   `json:"to_time"`
+	Query           sql.NullString `json:"query"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	CursorID        sql.NullInt64  `json:"cursor_id"`
+	PageSize        int32          `json:"page_size"`
+}
+
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountTransfers,
+		arg.AccountID,
+		arg.IncludeSent,
+		arg.IncludeReceived,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Query,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.SenderID,
+			&i.RecipientID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.RecipientAmount,
+			&i.ExchangeRate,
+			&i.QuoteID,
+			&i.Kind,
+			&i.ParentID,
+			&i.ExternalRef,
+			&i.BatchID,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
+			&i.CreatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockTransferUsage = `-- name: LockTransferUsage :exec
+SELECT pg_advisory_xact_lock(hashtextextended('transfer_usage:' || $1::text || ':' || $2::bigint, 0))
+`
+
+type LockTransferUsageParams struct {
+	Currency string `json:"currency"`
+	OwnerID  int64  `json:"owner_id"`
+}
+
+func (q *Queries) LockTransferUsage(ctx context.Context, arg LockTransferUsageParams) error {
+	_, err := q.db.ExecContext(ctx, lockTransferUsage, arg.Currency, arg.OwnerID)
+	return err
+}
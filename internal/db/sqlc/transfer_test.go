@@ -1,3 +1,101 @@
 package db
 
-// TODO: GetTransfer test
\ No newline at end of file
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TODO: GetTransfer test
+
+func TestListAccountTransfers(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t)
+
+	for i := int64(1); i <= 5; i++ {
+		senderID, recipientID := account1.ID, account2.ID
+		if i%2 == 0 {
+			senderID, recipientID = account2.ID, account1.ID
+		}
+
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			SenderID:    senderID,
+			RecipientID: recipientID,
+			Amount:      i,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListAccountTransfersParams{
+		AccountID:       account1.ID,
+		IncludeSent:     true,
+		IncludeReceived: true,
+		PageSize:        3,
+	}
+
+	page1, err := testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 3)
+	require.Equal(t, int64(5), page1[0].Amount)
+
+	last := page1[len(page1)-1]
+	arg.CursorCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+	arg.CursorID = sql.NullInt64{Int64: last.ID, Valid: true}
+
+	page2, err := testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 2)
+	require.Equal(t, int64(1), page2[1].Amount)
+
+	sent, err := testQueries.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID:   account1.ID,
+		IncludeSent: true,
+		MinAmount:   sql.NullInt64{Int64: 2, Valid: true},
+		PageSize:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, sent, 2)
+	for _, transfer := range sent {
+		require.Equal(t, account1.ID, transfer.SenderID)
+		require.GreaterOrEqual(t, transfer.Amount, int64(2))
+	}
+}
+
+func TestListAccountTransfersSearch(t *testing.T) {
+	sender, recipient := createRandomAccountPair(t)
+
+	for _, arg := range []TransferTxParams{
+		{Memo: "Rent for March", Reference: "INV-2023-03"},
+		{Memo: "Pizza night", Metadata: json.RawMessage(`{"category": "food", "order": "A17"}`)},
+		{},
+	} {
+		arg.SenderID, arg.RecipientID, arg.Amount = sender.ID, recipient.ID, 10
+		result, err := testStore.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, arg.Memo, result.Transfer.Memo)
+	}
+
+	search := func(query string) []Transfer {
+		transfers, err := testQueries.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+			AccountID:       recipient.ID,
+			IncludeSent:     true,
+			IncludeReceived: true,
+			Query:           sql.NullString{String: query, Valid: true},
+			PageSize:        10,
+		})
+		require.NoError(t, err)
+		return transfers
+	}
+
+	found := search("rent")
+	require.Len(t, found, 1)
+	require.Equal(t, "INV-2023-03", found[0].Reference)
+
+	found = search("food")
+	require.Len(t, found, 1)
+	require.JSONEq(t, `{"category": "food", "order": "A17"}`, string(found[0].Metadata))
+
+	require.Empty(t, search("pizza -night"))
+	require.Len(t, search("rent or pizza"), 2)
+}
@@ -16,7 +16,7 @@ INSERT INTO users (
     password
 )
 VALUES ($1, $2, $3)
-RETURNING id, username, email, password, password_changed_at, created_at
+RETURNING id, username, email, password, password_changed_at, created_at, role
 `
 
 type CreateUserParams struct {
@@ -35,12 +35,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, password, password_changed_at, created_at FROM users
+SELECT id, username, email, password, password_changed_at, created_at, role FROM users
 WHERE id = $1
 LIMIT 1
 `
@@ -55,12 +56,35 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.Password,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, username, email, password, password_changed_at, created_at, role FROM users
+WHERE lower(email) = lower($1)
+  AND (SELECT count(*) FROM users WHERE lower(email) = lower($1)) = 1
+LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password, password_changed_at, created_at FROM users
+SELECT id, username, email, password, password_changed_at, created_at, role FROM users
 WHERE username = $1
 LIMIT 1
 `
@@ -75,6 +99,7 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.Password,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"github.com/stretchr/testify/require"
 	"gobank/internal/auth"
 	"gobank/internal/util"
+	"strings"
 	"testing"
 )
 
@@ -36,3 +38,31 @@ func TestCreateUser(t *testing.T) {
 	// TODO with role
 	// createRandomUser(t)
 }
+
+func TestGetUserByEmail(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2, err := testQueries.GetUserByEmail(context.Background(), user1.Email)
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user2.ID)
+	require.Equal(t, user1.Username, user2.Username)
+
+	// emails are matched case-insensitively
+	user3, err := testQueries.GetUserByEmail(context.Background(), strings.ToUpper(user1.Email))
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user3.ID)
+}
+
+func TestGetUserByEmailAmbiguous(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	// the same email in another case, users.email is only unique case-sensitively
+	_, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+		Username: util.RandomName(),
+		Email:    strings.ToUpper(user1.Email),
+		Password: user1.Password,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetUserByEmail(context.Background(), user1.Email)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camt053TextSize is the longest unstructured remittance information, longer texts take several Ustrd elements.
+const camt053TextSize = 140
+
+// camt053Writer writes a BankToCustomerStatement (camt.053.001.02) with a single Stmt.
+type camt053Writer struct {
+	x         *xmlStream
+	statement Statement
+}
+
+func newCamt053Writer(w io.Writer) *camt053Writer {
+	return &camt053Writer{
+		x: newXMLStream(w),
+	}
+}
+
+func formatISOTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func creditDebitIndicator(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func (c *camt053Writer) amount(amount int64) {
+	if amount < 0 {
+		amount = -amount
+	}
+	c.x.leaf("Amt", FormatAmount(amount), xml.Attr{Name: xml.Name{Local: "Ccy"}, Value: c.statement.Currency})
+}
+
+func (c *camt053Writer) Begin(statement Statement) error {
+	c.statement = statement
+	x := c.x
+
+	id := fmt.Sprintf("STMT-%d-%d", statement.AccountID, statement.GeneratedAt.Unix())
+
+	x.procInst("xml", `version="1.0" encoding="UTF-8"`)
+	x.start("Document", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: camt053Namespace})
+	x.start("BkToCstmrStmt")
+
+	x.start("GrpHdr")
+	x.leaf("MsgId", id)
+	x.leaf("CreDtTm", formatISOTime(statement.GeneratedAt))
+	x.end("GrpHdr")
+
+	x.start("Stmt")
+	x.leaf("Id", id)
+	x.leaf("CreDtTm", formatISOTime(statement.GeneratedAt))
+	x.start("FrToDt")
+	x.leaf("FrDtTm", formatISOTime(statement.From))
+	x.leaf("ToDtTm", formatISOTime(statement.To))
+	x.end("FrToDt")
+
+	x.start("Acct")
+	x.start("Id")
+	x.start("Othr")
+	x.leaf("Id", strconv.FormatInt(statement.AccountID, 10))
+	x.end("Othr")
+	x.end("Id")
+	x.leaf("Ccy", statement.Currency)
+	x.end("Acct")
+
+	c.balance("OPBD", statement.OpeningBalance, statement.From)
+	c.balance("CLBD", statement.ClosingBalance, statement.To)
+
+	x.start("TxsSummry")
+	x.start("TtlNtries")
+	x.leaf("NbOfNtries", strconv.FormatInt(statement.CreditCount+statement.DebitCount, 10))
+	x.end("TtlNtries")
+	x.start("TtlCdtNtries")
+	x.leaf("NbOfNtries", strconv.FormatInt(statement.CreditCount, 10))
+	x.leaf("Sum", FormatAmount(statement.TotalIn))
+	x.end("TtlCdtNtries")
+	x.start("TtlDbtNtries")
+	x.leaf("NbOfNtries", strconv.FormatInt(statement.DebitCount, 10))
+	x.leaf("Sum", FormatAmount(statement.TotalOut))
+	x.end("TtlDbtNtries")
+	x.end("TxsSummry")
+
+	return x.err
+}
+
+func (c *camt053Writer) balance(code string, amount int64, at time.Time) {
+	x := c.x
+
+	x.start("Bal")
+	x.start("Tp")
+	x.start("CdOrPrtry")
+	x.leaf("Cd", code)
+	x.end("CdOrPrtry")
+	x.end("Tp")
+	c.amount(amount)
+	x.leaf("CdtDbtInd", creditDebitIndicator(amount))
+	x.start("Dt")
+	x.leaf("DtTm", formatISOTime(at))
+	x.end("Dt")
+	x.end("Bal")
+}
+
+func (c *camt053Writer) WriteLine(line Line) error {
+	x := c.x
+
+	x.start("Ntry")
+	x.leaf("NtryRef", strconv.FormatInt(line.EntryID, 10))
+	c.amount(line.Amount)
+	x.leaf("CdtDbtInd", creditDebitIndicator(line.Amount))
+	x.leaf("Sts", "BOOK")
+	x.start("BookgDt")
+	x.leaf("DtTm", formatISOTime(line.BookedAt))
+	x.end("BookgDt")
+	x.start("ValDt")
+	x.leaf("DtTm", formatISOTime(line.BookedAt))
+	x.end("ValDt")
+	x.leaf("AcctSvcrRef", strconv.FormatInt(line.EntryID, 10))
+	x.start("BkTxCd")
+	x.start("Prtry")
+	x.leaf("Cd", "TRANSFER")
+	x.leaf("Issr", bankID)
+	x.end("Prtry")
+	x.end("BkTxCd")
+
+	if line.TransferID != 0 {
+		x.start("NtryDtls")
+		x.start("TxDtls")
+		x.start("Refs")
+		x.leaf("EndToEndId", formatID(line.TransferID))
+		x.end("Refs")
+		if line.Memo != "" || line.Reference != "" {
+			x.start("RmtInf")
+			for _, info := range append(splitText(line.Memo, camt053TextSize), splitText(line.Reference, camt053TextSize)...) {
+				x.leaf("Ustrd", info)
+			}
+			x.end("RmtInf")
+		}
+		x.end("TxDtls")
+		x.end("NtryDtls")
+	}
+
+	x.end("Ntry")
+
+	return x.err
+}
+
+func (c *camt053Writer) End() error {
+	c.x.end("Stmt")
+	c.x.end("BkToCstmrStmt")
+	c.x.end("Document")
+	return c.x.flush()
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"booked_at", "entry_id", "transfer_id", "counterparty_account_id", "amount", "balance", "currency", "memo", "reference"}
+
+type csvWriter struct {
+	w        *csv.Writer
+	currency string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{
+		w: csv.NewWriter(w),
+	}
+}
+
+func (c *csvWriter) Begin(statement Statement) error {
+	c.currency = statement.Currency
+	return c.w.Write(csvHeader)
+}
+
+func (c *csvWriter) WriteLine(line Line) error {
+	return c.w.Write([]string{
+		line.BookedAt.UTC().Format(time.RFC3339),
+		strconv.FormatInt(line.EntryID, 10),
+		formatID(line.TransferID),
+		formatID(line.CounterpartyAccountID),
+		FormatAmount(line.Amount),
+		FormatAmount(line.Balance),
+		c.currency,
+		csvText(line.Memo),
+		csvText(line.Reference),
+	})
+}
+
+// csvText quotes free text set by the counterparty, so spreadsheets don't evaluate it as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
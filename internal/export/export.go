@@ -0,0 +1,153 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bankID identifies this bank in the exported files.
+const bankID = "GOBANK"
+
+type Format string
+
+const (
+	CSV     Format = "csv"
+	OFX     Format = "ofx"
+	Camt053 Format = "camt053"
+)
+
+// Statement describes the exported period, it is known before the first line is written.
+type Statement struct {
+	AccountID      int64
+	Currency       string
+	From           time.Time
+	To             time.Time
+	GeneratedAt    time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	TotalIn        int64
+	TotalOut       int64
+	CreditCount    int64
+	DebitCount     int64
+}
+
+type Line struct {
+	EntryID               int64
+	TransferID            int64
+	CounterpartyAccountID int64
+	Memo                  string
+	Reference             string
+	Amount                int64
+	Balance               int64
+	BookedAt              time.Time
+}
+
+// Writer writes a statement line by line, so exports of any length use constant memory.
+// Begin must be called once before the lines and End once after them.
+type Writer interface {
+	Begin(statement Statement) error
+	WriteLine(line Line) error
+	End() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w), nil
+	case OFX:
+		return newOFXWriter(w), nil
+	case Camt053:
+		return newCamt053Writer(w), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(s)); format {
+	case CSV, OFX, Camt053:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q, expected one of: csv, ofx, camt053", s)
+}
+
+// FormatFromAccept picks the format of the first supported media type in an Accept header,
+// an empty header or a wildcard means csv.
+func FormatFromAccept(accept string) (Format, error) {
+	if strings.TrimSpace(accept) == "" {
+		return CSV, nil
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "text/csv", "*/*", "text/*":
+			return CSV, nil
+		case "application/x-ofx", "application/ofx":
+			return OFX, nil
+		case "application/xml", "text/xml", "application/vnd.iso20022.camt.053+xml":
+			return Camt053, nil
+		}
+	}
+
+	return "", fmt.Errorf("none of the accepted media types are supported: %s", accept)
+}
+
+func ContentType(format Format) string {
+	switch format {
+	case OFX:
+		return "application/x-ofx"
+	case Camt053:
+		return "application/xml; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func FileExtension(format Format) string {
+	switch format {
+	case OFX:
+		return "ofx"
+	case Camt053:
+		return "xml"
+	}
+	return "csv"
+}
+
+// FormatAmount formats an amount in minor units as a decimal with two places, e.g. -1234 as "-12.34".
+func FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// splitText cuts s in parts of at most size characters, there is no part when s is empty.
+func splitText(s string, size int) []string {
+	var parts []string
+	runes := []rune(s)
+	for len(runes) > 0 {
+		n := size
+		if len(runes) < n {
+			n = len(runes)
+		}
+		parts = append(parts, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return parts
+}
@@ -0,0 +1,216 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testStatement() (Statement, []Line) {
+	from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	statement := Statement{
+		AccountID:      7,
+		Currency:       util.USD,
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		GeneratedAt:    from.AddDate(0, 1, 1),
+		OpeningBalance: 1000,
+		ClosingBalance: 1150,
+		TotalIn:        250,
+		TotalOut:       100,
+		CreditCount:    1,
+		DebitCount:     1,
+	}
+
+	lines := []Line{
+		{EntryID: 1, TransferID: 10, CounterpartyAccountID: 3, Memo: "rent", Reference: "INV-7", Amount: 250, Balance: 1250, BookedAt: from.Add(time.Hour)},
+		{EntryID: 2, TransferID: 11, CounterpartyAccountID: 4, Amount: -100, Balance: 1150, BookedAt: from.Add(2 * time.Hour)},
+	}
+
+	return statement, lines
+}
+
+func writeStatement(t *testing.T, format Format) string {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+
+	statement, lines := testStatement()
+	require.NoError(t, w.Begin(statement))
+	for _, line := range lines {
+		require.NoError(t, w.WriteLine(line))
+	}
+	require.NoError(t, w.End())
+
+	return buf.String()
+}
+
+func TestCSVWriter(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(writeStatement(t, CSV))).ReadAll()
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{
+		csvHeader,
+		{"2023-03-01T01:00:00Z", "1", "10", "3", "2.50", "12.50", "USD", "rent", "INV-7"},
+		{"2023-03-01T02:00:00Z", "2", "11", "4", "-1.00", "11.50", "USD", "", ""},
+	}, records)
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(CSV, &buf)
+	require.NoError(t, err)
+
+	statement, _ := testStatement()
+	require.NoError(t, w.Begin(statement))
+	for _, text := range []string{`=HYPERLINK("http://x")`, "+1", "-1", "@SUM(A1)", "\tx", "\rx"} {
+		require.NoError(t, w.WriteLine(Line{EntryID: 1, Memo: text, Reference: text}))
+	}
+	require.NoError(t, w.WriteLine(Line{EntryID: 2, Memo: "rent = 100", Reference: "INV-7"}))
+	require.NoError(t, w.End())
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+
+	for _, record := range records[1 : len(records)-1] {
+		require.Equal(t, "'", record[7][:1])
+		require.Equal(t, "'", record[8][:1])
+	}
+
+	last := records[len(records)-1]
+	require.Equal(t, "rent = 100", last[7])
+	require.Equal(t, "INV-7", last[8])
+}
+
+func TestOFXWriter(t *testing.T) {
+	out := writeStatement(t, OFX)
+	require.True(t, strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`))
+	require.Contains(t, out, `<?OFX OFXHEADER="200" VERSION="220"`)
+
+	var doc struct {
+		Currency     string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>CURDEF"`
+		AccountID    string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKACCTFROM>ACCTID"`
+		Transactions []struct {
+			Type   string `xml:"TRNTYPE"`
+			Posted string `xml:"DTPOSTED"`
+			Amount string `xml:"TRNAMT"`
+			FitID  string `xml:"FITID"`
+			Memo   string `xml:"MEMO"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+		LedgerBalance string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>LEDGERBAL>BALAMT"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(out), &doc))
+
+	require.Equal(t, util.USD, doc.Currency)
+	require.Equal(t, "7", doc.AccountID)
+	require.Len(t, doc.Transactions, 2)
+	require.Equal(t, "CREDIT", doc.Transactions[0].Type)
+	require.Equal(t, "20230301010000.000[0:GMT]", doc.Transactions[0].Posted)
+	require.Equal(t, "2.50", doc.Transactions[0].Amount)
+	require.Equal(t, "rent INV-7", doc.Transactions[0].Memo)
+	require.Equal(t, "DEBIT", doc.Transactions[1].Type)
+	require.Equal(t, "-1.00", doc.Transactions[1].Amount)
+	require.Equal(t, "2", doc.Transactions[1].FitID)
+	require.Equal(t, "11.50", doc.LedgerBalance)
+}
+
+func TestCamt053Writer(t *testing.T) {
+	out := writeStatement(t, Camt053)
+
+	var doc struct {
+		XMLName xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+		Stmt    struct {
+			AccountID string `xml:"Acct>Id>Othr>Id"`
+			Currency  string `xml:"Acct>Ccy"`
+			Balances  []struct {
+				Code   string `xml:"Tp>CdOrPrtry>Cd"`
+				Amount string `xml:"Amt"`
+				Ind    string `xml:"CdtDbtInd"`
+			} `xml:"Bal"`
+			Entries []struct {
+				Ref    string `xml:"NtryRef"`
+				Amount struct {
+					Value    string `xml:",chardata"`
+					Currency string `xml:"Ccy,attr"`
+				} `xml:"Amt"`
+				Ind        string   `xml:"CdtDbtInd"`
+				EndToEndID string   `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+				Info       []string `xml:"NtryDtls>TxDtls>RmtInf>Ustrd"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(out), &doc))
+
+	require.Equal(t, "7", doc.Stmt.AccountID)
+	require.Equal(t, util.USD, doc.Stmt.Currency)
+
+	require.Len(t, doc.Stmt.Balances, 2)
+	require.Equal(t, "OPBD", doc.Stmt.Balances[0].Code)
+	require.Equal(t, "10.00", doc.Stmt.Balances[0].Amount)
+	require.Equal(t, "CLBD", doc.Stmt.Balances[1].Code)
+	require.Equal(t, "11.50", doc.Stmt.Balances[1].Amount)
+
+	require.Len(t, doc.Stmt.Entries, 2)
+	require.Equal(t, "1", doc.Stmt.Entries[0].Ref)
+	require.Equal(t, "2.50", doc.Stmt.Entries[0].Amount.Value)
+	require.Equal(t, util.USD, doc.Stmt.Entries[0].Amount.Currency)
+	require.Equal(t, "CRDT", doc.Stmt.Entries[0].Ind)
+	require.Equal(t, "10", doc.Stmt.Entries[0].EndToEndID)
+	require.Equal(t, []string{"rent", "INV-7"}, doc.Stmt.Entries[0].Info)
+	require.Empty(t, doc.Stmt.Entries[1].Info)
+	require.Equal(t, "1.00", doc.Stmt.Entries[1].Amount.Value)
+	require.Equal(t, "DBIT", doc.Stmt.Entries[1].Ind)
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "0.00", FormatAmount(0))
+	require.Equal(t, "0.05", FormatAmount(5))
+	require.Equal(t, "12.34", FormatAmount(1234))
+	require.Equal(t, "-12.34", FormatAmount(-1234))
+	require.Equal(t, "-0.01", FormatAmount(-1))
+}
+
+func TestFormatFromAccept(t *testing.T) {
+	cases := map[string]Format{
+		"":                                  CSV,
+		"*/*":                               CSV,
+		"text/csv":                          CSV,
+		"application/x-ofx":                 OFX,
+		"application/xml":                   Camt053,
+		"application/json, application/xml": Camt053,
+		"text/html;q=0.9, application/ofx":  OFX,
+	}
+
+	for accept, expected := range cases {
+		format, err := FormatFromAccept(accept)
+		require.NoError(t, err, accept)
+		require.Equal(t, expected, format, accept)
+	}
+
+	_, err := FormatFromAccept("application/json")
+	require.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("OFX")
+	require.NoError(t, err)
+	require.Equal(t, OFX, format)
+
+	_, err = ParseFormat("pdf")
+	require.Error(t, err)
+}
+
+func TestSplitText(t *testing.T) {
+	require.Empty(t, splitText("", 3))
+	require.Equal(t, []string{"abc"}, splitText("abc", 3))
+	require.Equal(t, []string{"abc", "d"}, splitText("abcd", 3))
+	require.Equal(t, []string{"äöü", "ß"}, splitText("äöüß", 3))
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ofxMemoSize is the longest MEMO allowed by OFX, a longer memo and reference are cut.
+const ofxMemoSize = 255
+
+type ofxWriter struct {
+	x         *xmlStream
+	statement Statement
+}
+
+func newOFXWriter(w io.Writer) *ofxWriter {
+	return &ofxWriter{
+		x: newXMLStream(w),
+	}
+}
+
+func formatOFXTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+func (o *ofxWriter) Begin(statement Statement) error {
+	o.statement = statement
+	x := o.x
+
+	x.procInst("xml", `version="1.0" encoding="UTF-8" standalone="no"`)
+	x.procInst("OFX", `OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"`)
+	x.start("OFX")
+
+	x.start("SIGNONMSGSRSV1")
+	x.start("SONRS")
+	o.writeStatus()
+	x.leaf("DTSERVER", formatOFXTime(statement.GeneratedAt))
+	x.leaf("LANGUAGE", "ENG")
+	x.end("SONRS")
+	x.end("SIGNONMSGSRSV1")
+
+	x.start("BANKMSGSRSV1")
+	x.start("STMTTRNRS")
+	x.leaf("TRNUID", "0")
+	o.writeStatus()
+	x.start("STMTRS")
+	x.leaf("CURDEF", statement.Currency)
+	x.start("BANKACCTFROM")
+	x.leaf("BANKID", bankID)
+	x.leaf("ACCTID", strconv.FormatInt(statement.AccountID, 10))
+	x.leaf("ACCTTYPE", "CHECKING")
+	x.end("BANKACCTFROM")
+	x.start("BANKTRANLIST")
+	x.leaf("DTSTART", formatOFXTime(statement.From))
+	x.leaf("DTEND", formatOFXTime(statement.To))
+
+	return x.err
+}
+
+func (o *ofxWriter) writeStatus() {
+	o.x.start("STATUS")
+	o.x.leaf("CODE", "0")
+	o.x.leaf("SEVERITY", "INFO")
+	o.x.end("STATUS")
+}
+
+func (o *ofxWriter) WriteLine(line Line) error {
+	x := o.x
+
+	trnType := "CREDIT"
+	if line.Amount < 0 {
+		trnType = "DEBIT"
+	}
+
+	x.start("STMTTRN")
+	x.leaf("TRNTYPE", trnType)
+	x.leaf("DTPOSTED", formatOFXTime(line.BookedAt))
+	x.leaf("TRNAMT", FormatAmount(line.Amount))
+	x.leaf("FITID", strconv.FormatInt(line.EntryID, 10))
+	if line.TransferID != 0 {
+		x.leaf("REFNUM", formatID(line.TransferID))
+	}
+	if line.CounterpartyAccountID != 0 {
+		x.leaf("NAME", "Account "+formatID(line.CounterpartyAccountID))
+	}
+	if memo := splitText(strings.TrimSpace(line.Memo+" "+line.Reference), ofxMemoSize); len(memo) > 0 {
+		x.leaf("MEMO", memo[0])
+	}
+	x.end("STMTTRN")
+
+	return x.err
+}
+
+func (o *ofxWriter) End() error {
+	x := o.x
+
+	x.end("BANKTRANLIST")
+	x.start("LEDGERBAL")
+	x.leaf("BALAMT", FormatAmount(o.statement.ClosingBalance))
+	x.leaf("DTASOF", formatOFXTime(o.statement.To))
+	x.end("LEDGERBAL")
+	x.end("STMTRS")
+	x.end("STMTTRNRS")
+	x.end("BANKMSGSRSV1")
+	x.end("OFX")
+
+	return x.flush()
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// xmlStream writes an xml document token by token. The first error is kept
+// and every later call is a no-op, so writers can check it once per element.
+type xmlStream struct {
+	enc *xml.Encoder
+	err error
+}
+
+func newXMLStream(w io.Writer) *xmlStream {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &xmlStream{
+		enc: enc,
+	}
+}
+
+func (x *xmlStream) token(t xml.Token) {
+	if x.err == nil {
+		x.err = x.enc.EncodeToken(t)
+	}
+}
+
+func (x *xmlStream) procInst(target string, inst string) {
+	x.token(xml.ProcInst{Target: target, Inst: []byte(inst)})
+}
+
+func (x *xmlStream) start(name string, attrs ...xml.Attr) {
+	x.token(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (x *xmlStream) end(name string) {
+	x.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (x *xmlStream) leaf(name string, value string, attrs ...xml.Attr) {
+	x.start(name, attrs...)
+	x.token(xml.CharData(value))
+	x.end(name)
+}
+
+func (x *xmlStream) flush() error {
+	if x.err == nil {
+		x.err = x.enc.Flush()
+	}
+	return x.err
+}
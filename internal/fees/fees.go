@@ -0,0 +1,189 @@
+package fees
+
+import (
+	"fmt"
+)
+
+// Transfer types the rules can match on.
+const (
+	TypeTransfer   = "transfer"
+	TypeFxTransfer = "fx_transfer"
+	TypeWithdrawal = "withdrawal"
+)
+
+// Charges of a rule.
+const (
+	ChargeFlat       = "flat"
+	ChargePercentage = "percentage"
+	ChargeTiered     = "tiered"
+)
+
+// Rule charges a fee on the transfers of a type, currency and amount band.
+// Amounts are in minor units of the sender currency, rates in basis points of the transfer amount.
+type Rule struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Currency matches any currency when empty.
+	Currency string `json:"currency"`
+	// MinAmount and MaxAmount bound the band of the rule, inclusive. A zero MaxAmount leaves the band open.
+	MinAmount int64 `json:"min_amount"`
+	MaxAmount int64 `json:"max_amount"`
+
+	Charge string `json:"charge"`
+	// Flat is the fee of a flat rule.
+	Flat int64 `json:"flat"`
+	// RateBps, Min and Max make the fee of a percentage rule, zero Min and Max don't cap it.
+	RateBps int64 `json:"rate_bps"`
+	Min     int64 `json:"min"`
+	Max     int64 `json:"max"`
+	// Tiers charge each part of the amount at the rate of the tier it falls into.
+	Tiers []Tier `json:"tiers"`
+}
+
+// Tier applies RateBps to the part of the amount up to UpTo, past the previous tier.
+// A zero UpTo, only allowed on the last tier, covers the rest of the amount.
+type Tier struct {
+	UpTo    int64 `json:"up_to"`
+	RateBps int64 `json:"rate_bps"`
+}
+
+// Transfer is what fees are evaluated on.
+type Transfer struct {
+	Type     string
+	Currency string
+	Amount   int64
+}
+
+// Fee is the part of the total fee charged by one rule.
+type Fee struct {
+	Rule   string `json:"rule"`
+	Amount int64  `json:"amount"`
+}
+
+// Engine evaluates fee rules, every rule that matches a transfer charges its own fee.
+type Engine struct {
+	rules []Rule
+}
+
+// New returns an engine evaluating the rules in order, they are expected to be valid.
+func New(rules []Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Validate checks that every rule can be evaluated.
+func Validate(rules []Rule) error {
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("fee rule %d %q: %w", i, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the fees charged on a transfer and their total, zero fees are left out.
+func (e *Engine) Evaluate(t Transfer) ([]Fee, int64) {
+	var res []Fee
+	var total int64
+
+	for _, rule := range e.rules {
+		if !rule.matches(t) {
+			continue
+		}
+
+		amount := rule.fee(t.Amount)
+		if amount > 0 {
+			res = append(res, Fee{Rule: rule.Name, Amount: amount})
+			total += amount
+		}
+	}
+
+	return res, total
+}
+
+func (r Rule) matches(t Transfer) bool {
+	return r.Type == t.Type &&
+		(r.Currency == "" || r.Currency == t.Currency) &&
+		t.Amount >= r.MinAmount &&
+		(r.MaxAmount == 0 || t.Amount <= r.MaxAmount)
+}
+
+func (r Rule) fee(amount int64) int64 {
+	switch r.Charge {
+	case ChargeFlat:
+		return r.Flat
+	case ChargePercentage:
+		fee := percentage(amount, r.RateBps)
+		if fee < r.Min {
+			fee = r.Min
+		}
+		if r.Max > 0 && fee > r.Max {
+			fee = r.Max
+		}
+		return fee
+	case ChargeTiered:
+		var fee, from int64
+		for _, tier := range r.Tiers {
+			to := amount
+			if tier.UpTo > 0 && tier.UpTo < amount {
+				to = tier.UpTo
+			}
+			if to > from {
+				fee += percentage(to-from, tier.RateBps)
+			}
+			if to == amount {
+				break
+			}
+			from = to
+		}
+		return fee
+	}
+	return 0
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch r.Type {
+	case TypeTransfer, TypeFxTransfer, TypeWithdrawal:
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+
+	if r.MinAmount < 0 || r.MaxAmount < 0 || (r.MaxAmount > 0 && r.MaxAmount < r.MinAmount) {
+		return fmt.Errorf("invalid amount band %d-%d", r.MinAmount, r.MaxAmount)
+	}
+
+	switch r.Charge {
+	case ChargeFlat:
+		if r.Flat <= 0 {
+			return fmt.Errorf("flat fee must be positive")
+		}
+	case ChargePercentage:
+		if r.RateBps <= 0 || r.Min < 0 || r.Max < 0 || (r.Max > 0 && r.Max < r.Min) {
+			return fmt.Errorf("invalid percentage %d bps, min %d, max %d", r.RateBps, r.Min, r.Max)
+		}
+	case ChargeTiered:
+		if len(r.Tiers) == 0 {
+			return fmt.Errorf("tiers are required")
+		}
+		var upTo int64
+		for i, tier := range r.Tiers {
+			last := i == len(r.Tiers)-1
+			if tier.RateBps < 0 || (tier.UpTo <= upTo && !(last && tier.UpTo == 0)) {
+				return fmt.Errorf("invalid tier %d", i)
+			}
+			upTo = tier.UpTo
+		}
+	default:
+		return fmt.Errorf("unknown charge %q", r.Charge)
+	}
+
+	return nil
+}
+
+// percentage rounds half up.
+func percentage(amount int64, rateBps int64) int64 {
+	return (amount*rateBps + 5000) / 10000
+}
@@ -0,0 +1,111 @@
+package fees
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	rules, err := LoadFile("testdata/fees.json")
+	require.NoError(t, err)
+	engine := New(rules)
+
+	testCases := []struct {
+		name     string
+		transfer Transfer
+		fees     []Fee
+		total    int64
+	}{
+		{
+			name:     "no matching rule",
+			transfer: Transfer{Type: TypeTransfer, Currency: "EUR", Amount: 100},
+		},
+		{
+			name:     "percentage min",
+			transfer: Transfer{Type: TypeFxTransfer, Currency: "USD", Amount: 1000},
+			fees:     []Fee{{Rule: "fx conversion", Amount: 100}},
+			total:    100,
+		},
+		{
+			name:     "percentage",
+			transfer: Transfer{Type: TypeFxTransfer, Currency: "USD", Amount: 100000},
+			fees:     []Fee{{Rule: "fx conversion", Amount: 500}},
+			total:    500,
+		},
+		{
+			name:     "percentage max",
+			transfer: Transfer{Type: TypeFxTransfer, Currency: "USD", Amount: 10000000},
+			fees:     []Fee{{Rule: "fx conversion", Amount: 5000}},
+			total:    5000,
+		},
+		{
+			name:     "flat in currency",
+			transfer: Transfer{Type: TypeWithdrawal, Currency: "EUR", Amount: 100},
+			fees:     []Fee{{Rule: "EUR withdrawal", Amount: 150}},
+			total:    150,
+		},
+		{
+			name:     "flat in other currency",
+			transfer: Transfer{Type: TypeWithdrawal, Currency: "USD", Amount: 100},
+		},
+		{
+			name:     "first tier",
+			transfer: Transfer{Type: TypeTransfer, Currency: "RUB", Amount: 1000000},
+			fees:     []Fee{{Rule: "large transfer", Amount: 1000}},
+			total:    1000,
+		},
+		{
+			name:     "both tiers",
+			transfer: Transfer{Type: TypeTransfer, Currency: "RUB", Amount: 7000000},
+			fees:     []Fee{{Rule: "large transfer", Amount: 6000}},
+			total:    6000,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fees, total := engine.Evaluate(tc.transfer)
+			require.Equal(t, tc.fees, fees)
+			require.Equal(t, tc.total, total)
+		})
+	}
+}
+
+func TestEvaluateAllMatchingRules(t *testing.T) {
+	engine := New([]Rule{
+		{Name: "base", Type: TypeTransfer, Charge: ChargeFlat, Flat: 10},
+		{Name: "share", Type: TypeTransfer, Charge: ChargePercentage, RateBps: 100},
+	})
+
+	fees, total := engine.Evaluate(Transfer{Type: TypeTransfer, Currency: "EUR", Amount: 1050})
+	require.Equal(t, []Fee{{Rule: "base", Amount: 10}, {Rule: "share", Amount: 11}}, fees)
+	require.Equal(t, int64(21), total)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(nil))
+
+	invalid := []Rule{
+		{Type: TypeTransfer, Charge: ChargeFlat, Flat: 10},
+		{Name: "type", Type: "deposit", Charge: ChargeFlat, Flat: 10},
+		{Name: "band", Type: TypeTransfer, MinAmount: 10, MaxAmount: 5, Charge: ChargeFlat, Flat: 10},
+		{Name: "flat", Type: TypeTransfer, Charge: ChargeFlat},
+		{Name: "caps", Type: TypeTransfer, Charge: ChargePercentage, RateBps: 10, Min: 10, Max: 5},
+		{Name: "no tiers", Type: TypeTransfer, Charge: ChargeTiered},
+		{Name: "open tier", Type: TypeTransfer, Charge: ChargeTiered, Tiers: []Tier{{RateBps: 1}, {UpTo: 10, RateBps: 1}}},
+		{Name: "charge", Type: TypeTransfer, Charge: "free"},
+	}
+
+	for _, rule := range invalid {
+		require.Error(t, Validate([]Rule{rule}), rule.Name)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	rules, err := LoadFile("")
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	_, err = LoadFile("testdata/missing.json")
+	require.Error(t, err)
+}
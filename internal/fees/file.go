@@ -0,0 +1,34 @@
+package fees
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type file struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadFile reads the rules of a JSON file, an empty path means no fees.
+func LoadFile(path string) ([]Rule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("cannot parse fee rules %s: %w", path, err)
+	}
+
+	if err := Validate(f.Rules); err != nil {
+		return nil, err
+	}
+
+	return f.Rules, nil
+}
@@ -0,0 +1,75 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const FakeName = "fake"
+
+// FakeDeclinedInstrument is declined by the fake source, to exercise the failure paths.
+const FakeDeclinedInstrument = "declined"
+
+// Operation is a request executed by the fake source.
+type Operation struct {
+	Request
+	Payout bool
+}
+
+// Fake is an in-process funding source that accepts every instrument except FakeDeclinedInstrument.
+// It keeps the operations it executed, a reference is only executed once.
+type Fake struct {
+	mu         sync.Mutex
+	operations map[string]Operation
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		operations: make(map[string]Operation),
+	}
+}
+
+func (f *Fake) Name() string {
+	return FakeName
+}
+
+func (f *Fake) Collect(ctx context.Context, req Request) error {
+	return f.execute(req, false)
+}
+
+func (f *Fake) Payout(ctx context.Context, req Request) error {
+	return f.execute(req, true)
+}
+
+// Operations returns the executed operations by reference.
+func (f *Fake) Operations() map[string]Operation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res := make(map[string]Operation, len(f.operations))
+	for ref, op := range f.operations {
+		res[ref] = op
+	}
+	return res
+}
+
+func (f *Fake) execute(req Request, payout bool) error {
+	if req.Instrument == FakeDeclinedInstrument {
+		return fmt.Errorf("%w: instrument %s", ErrDeclined, req.Instrument)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	op := Operation{Request: req, Payout: payout}
+	if prev, ok := f.operations[req.Reference]; ok {
+		if prev != op {
+			return fmt.Errorf("%w: reference %s was already used for another operation", ErrDeclined, req.Reference)
+		}
+		return nil
+	}
+
+	f.operations[req.Reference] = op
+	return nil
+}
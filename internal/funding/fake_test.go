@@ -0,0 +1,42 @@
+package funding
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFake(t *testing.T) {
+	source, err := NewSource(FakeName)
+	require.NoError(t, err)
+	fake := source.(*Fake)
+
+	req := Request{Reference: "ref-1", AccountID: 1, Amount: 100, Currency: "EUR", Instrument: "card"}
+	require.NoError(t, fake.Collect(context.Background(), req))
+
+	// same reference, same operation: executed once
+	require.NoError(t, fake.Collect(context.Background(), req))
+	require.Len(t, fake.Operations(), 1)
+
+	err = fake.Payout(context.Background(), req)
+	require.ErrorIs(t, err, ErrDeclined)
+
+	req.Reference = "ref-2"
+	require.NoError(t, fake.Payout(context.Background(), req))
+
+	ops := fake.Operations()
+	require.Len(t, ops, 2)
+	require.False(t, ops["ref-1"].Payout)
+	require.True(t, ops["ref-2"].Payout)
+
+	req.Reference = "ref-3"
+	req.Instrument = FakeDeclinedInstrument
+	err = fake.Collect(context.Background(), req)
+	require.ErrorIs(t, err, ErrDeclined)
+	require.Len(t, fake.Operations(), 2)
+}
+
+func TestNewSourceUnknown(t *testing.T) {
+	_, err := NewSource("unknown")
+	require.Error(t, err)
+}
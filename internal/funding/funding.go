@@ -0,0 +1,40 @@
+package funding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrDeclined = errors.New("funding source declined the operation")
+
+// Request is an operation against the customer's external funding source, e.g. a card or a bank account.
+type Request struct {
+	// Reference identifies the operation, providers must not execute the same reference twice.
+	Reference string
+	AccountID int64
+	Amount    int64
+	Currency  string
+	// Instrument is the provider's token for the external source or destination of the money.
+	Instrument string
+}
+
+// Source moves money between customers' external funding sources and the bank's settlement accounts.
+// Errors wrapping ErrDeclined mean the money didn't move and retrying won't help.
+type Source interface {
+	Name() string
+	// Collect pulls money from the instrument, for a deposit.
+	Collect(ctx context.Context, req Request) error
+	// Payout pushes money to the instrument, for a withdrawal.
+	Payout(ctx context.Context, req Request) error
+}
+
+// NewSource returns the funding source configured by name.
+func NewSource(name string) (Source, error) {
+	switch name {
+	case FakeName:
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown funding provider %q", name)
+	}
+}
@@ -0,0 +1,192 @@
+package fx
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const ecbBaseCurrency = "EUR"
+
+// Rate is the price of one unit of Base in Quote currency, starting from EffectiveAt.
+type Rate struct {
+	Base        string
+	Quote       string
+	Value       *big.Rat
+	EffectiveAt time.Time
+}
+
+// Provider loads exchange rates from some source.
+type Provider interface {
+	Name() string
+	Rates(ctx context.Context) ([]Rate, error)
+}
+
+// ECBFileProvider reads the ECB euro foreign exchange reference rates XML,
+// both the daily file (eurofxref-daily.xml) and the history file (eurofxref-hist.xml).
+type ECBFileProvider struct {
+	path string
+}
+
+func NewECBFileProvider(path string) Provider {
+	return &ECBFileProvider{
+		path: path,
+	}
+}
+
+func (p *ECBFileProvider) Name() string {
+	return "ecb"
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func (p *ECBFileProvider) Rates(ctx context.Context) ([]Rate, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseECB(file)
+}
+
+// ParseECB parses rates in the ECB reference rates XML format, all rates are based in EUR.
+func ParseECB(r io.Reader) ([]Rate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("cannot decode ecb xml: %w", err)
+	}
+
+	var rates []Rate
+	for _, day := range envelope.Days {
+		effectiveAt, err := parseRateDate(day.Time)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range day.Rates {
+			value, err := ParseRate(r.Rate)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", day.Time, r.Currency, err)
+			}
+
+			rates = append(rates, Rate{
+				Base:        ecbBaseCurrency,
+				Quote:       strings.ToUpper(r.Currency),
+				Value:       value,
+				EffectiveAt: effectiveAt,
+			})
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("no rates found in ecb xml")
+	}
+
+	return rates, nil
+}
+
+// CSVFileProvider reads rates from a csv file with a "date,base,quote,rate" header,
+// dates are either YYYY-MM-DD or RFC 3339 timestamps.
+type CSVFileProvider struct {
+	path string
+}
+
+func NewCSVFileProvider(path string) Provider {
+	return &CSVFileProvider{
+		path: path,
+	}
+}
+
+func (p *CSVFileProvider) Name() string {
+	return "csv"
+}
+
+func (p *CSVFileProvider) Rates(ctx context.Context) ([]Rate, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseCSV(file)
+}
+
+var csvHeader = []string{"date", "base", "quote", "rate"}
+
+func ParseCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read csv header: %w", err)
+	}
+	for i, name := range csvHeader {
+		if strings.ToLower(strings.TrimSpace(header[i])) != name {
+			return nil, fmt.Errorf("invalid csv header: expected %s", strings.Join(csvHeader, ","))
+		}
+	}
+
+	var rates []Rate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		effectiveAt, err := parseRateDate(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		value, err := ParseRate(record[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rates = append(rates, Rate{
+			Base:        strings.ToUpper(record[1]),
+			Quote:       strings.ToUpper(record[2]),
+			Value:       value,
+			EffectiveAt: effectiveAt,
+		})
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("no rates found in csv")
+	}
+
+	return rates, nil
+}
+
+func parseRateDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid rate date %q", s)
+}
@@ -0,0 +1,70 @@
+package fx
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestECBFileProvider(t *testing.T) {
+	provider := NewECBFileProvider("testdata/eurofxref-hist.xml")
+
+	rates, err := provider.Rates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 6)
+
+	rate := rates[0]
+	require.Equal(t, util.EUR, rate.Base)
+	require.Equal(t, util.USD, rate.Quote)
+	require.Equal(t, "1.1216000000", FormatRate(rate.Value))
+	require.Equal(t, time.Date(2022, 2, 25, 0, 0, 0, 0, time.UTC), rate.EffectiveAt)
+
+	rate = rates[5]
+	require.Equal(t, util.RUB, rate.Quote)
+	require.Equal(t, time.Date(2022, 2, 24, 0, 0, 0, 0, time.UTC), rate.EffectiveAt)
+}
+
+func TestParseECBInvalid(t *testing.T) {
+	_, err := ParseECB(strings.NewReader("not xml"))
+	require.Error(t, err)
+
+	_, err = ParseECB(strings.NewReader(`<Envelope><Cube><Cube time="2022-02-25"><Cube currency="USD" rate="abc"/></Cube></Cube></Envelope>`))
+	require.Error(t, err)
+
+	_, err = ParseECB(strings.NewReader(`<Envelope><Cube></Cube></Envelope>`))
+	require.Error(t, err)
+}
+
+func TestCSVFileProvider(t *testing.T) {
+	provider := NewCSVFileProvider("testdata/rates.csv")
+
+	rates, err := provider.Rates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	require.Equal(t, util.EUR, rates[0].Base)
+	require.Equal(t, util.USD, rates[0].Quote)
+	require.Equal(t, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), rates[0].EffectiveAt)
+
+	require.Equal(t, util.USD, rates[1].Base)
+	require.Equal(t, util.RUB, rates[1].Quote)
+	require.Equal(t, "75.3100000000", FormatRate(rates[1].Value))
+	require.Equal(t, time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC), rates[1].EffectiveAt)
+}
+
+func TestParseCSVInvalid(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("day,from,to,price\n2023-03-01,EUR,USD,1.0642\n"))
+	require.Error(t, err)
+
+	_, err = ParseCSV(strings.NewReader("date,base,quote,rate\n01.03.2023,EUR,USD,1.0642\n"))
+	require.Error(t, err)
+
+	_, err = ParseCSV(strings.NewReader("date,base,quote,rate\n2023-03-01,EUR,USD\n"))
+	require.Error(t, err)
+
+	_, err = ParseCSV(strings.NewReader("date,base,quote,rate\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// RatePrecision is the number of decimal places rates are stored with, see the exchange_rates table.
+const RatePrecision = 10
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+var ErrAmountOverflow = errors.New("converted amount is too large")
+
+// ParseRate parses a decimal rate as stored in the database, e.g. "1.0642000000".
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid exchange rate %q", s)
+	}
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("exchange rate must be positive, got %q", s)
+	}
+	return rate, nil
+}
+
+// FormatRate formats a rate with RatePrecision decimal places.
+func FormatRate(rate *big.Rat) string {
+	return rate.FloatString(RatePrecision)
+}
+
+// Convert converts an amount in minor units with the given rate.
+// The result is rounded down, so the bank never credits more than it debits.
+// ErrAmountOverflow is returned when the result doesn't fit in an int64.
+func Convert(amount int64, rate *big.Rat) (int64, error) {
+	res := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	converted := new(big.Int).Quo(res.Num(), res.Denom())
+	if !converted.IsInt64() {
+		return 0, fmt.Errorf("%w: %d at rate %s", ErrAmountOverflow, amount, FormatRate(rate))
+	}
+	return converted.Int64(), nil
+}
+
+type pair struct {
+	base  string
+	quote string
+}
+
+// Table resolves the rate between two currencies from a set of known rates.
+type Table struct {
+	rates map[pair]*big.Rat
+}
+
+func NewTable() *Table {
+	return &Table{
+		rates: make(map[pair]*big.Rat),
+	}
+}
+
+// Add registers the rate for one unit of base in quote currency.
+func (t *Table) Add(base string, quote string, rate *big.Rat) {
+	t.rates[pair{base, quote}] = rate
+}
+
+// Rate returns the rate for one unit of base in quote currency.
+// When there is no direct rate it falls back to the inverse of the quote/base rate,
+// and then triangulates through a third currency known against both of them.
+func (t *Table) Rate(base string, quote string) (*big.Rat, error) {
+	if rate, ok := t.pairRate(base, quote); ok {
+		return rate, nil
+	}
+
+	for _, pivot := range t.currencies() {
+		if pivot == base || pivot == quote {
+			continue
+		}
+
+		baseRate, ok := t.pairRate(base, pivot)
+		if !ok {
+			continue
+		}
+
+		quoteRate, ok := t.pairRate(pivot, quote)
+		if !ok {
+			continue
+		}
+
+		return new(big.Rat).Mul(baseRate, quoteRate), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, base, quote)
+}
+
+func (t *Table) pairRate(base string, quote string) (*big.Rat, bool) {
+	if base == quote {
+		return big.NewRat(1, 1), true
+	}
+
+	if rate, ok := t.rates[pair{base, quote}]; ok {
+		return rate, true
+	}
+
+	if rate, ok := t.rates[pair{quote, base}]; ok {
+		return new(big.Rat).Inv(rate), true
+	}
+
+	return nil, false
+}
+
+// currencies returns every currency in the table in a stable order,
+// so triangulation always picks the same pivot.
+func (t *Table) currencies() []string {
+	seen := make(map[string]bool)
+	for p := range t.rates {
+		seen[p.base] = true
+		seen[p.quote] = true
+	}
+
+	res := make([]string, 0, len(seen))
+	for currency := range seen {
+		res = append(res, currency)
+	}
+
+	sort.Strings(res)
+	return res
+}
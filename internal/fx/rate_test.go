@@ -0,0 +1,90 @@
+package fx
+
+import (
+	"github.com/stretchr/testify/require"
+	"gobank/internal/util"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseRate(t *testing.T) {
+	rate, err := ParseRate("1.0642000000")
+	require.NoError(t, err)
+	require.Equal(t, "1.0642000000", FormatRate(rate))
+
+	_, err = ParseRate("abc")
+	require.Error(t, err)
+
+	_, err = ParseRate("0")
+	require.Error(t, err)
+
+	_, err = ParseRate("-1.5")
+	require.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	rate, err := ParseRate("1.0642")
+	require.NoError(t, err)
+
+	convert := func(amount int64, rate *big.Rat) int64 {
+		converted, err := Convert(amount, rate)
+		require.NoError(t, err)
+		return converted
+	}
+
+	require.Equal(t, int64(10642), convert(10000, rate))
+	require.Equal(t, int64(1), convert(1, rate))
+	require.Equal(t, int64(0), convert(0, rate))
+
+	rate, err = ParseRate("0.0099")
+	require.NoError(t, err)
+
+	require.Equal(t, int64(0), convert(100, rate))
+	require.Equal(t, int64(9), convert(1000, rate))
+
+	// the result doesn't wrap around when it is out of the int64 range
+	rate, err = ParseRate("92.5")
+	require.NoError(t, err)
+
+	require.Equal(t, int64(math.MaxInt64), convert(math.MaxInt64, big.NewRat(1, 1)))
+	_, err = Convert(math.MaxInt64/10, rate)
+	require.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func TestTableRate(t *testing.T) {
+	table := NewTable()
+	table.Add(util.EUR, util.USD, big.NewRat(5, 4))
+
+	rate, err := table.Rate(util.EUR, util.USD)
+	require.NoError(t, err)
+	require.Equal(t, "1.2500000000", FormatRate(rate))
+
+	rate, err = table.Rate(util.USD, util.EUR)
+	require.NoError(t, err)
+	require.Equal(t, "0.8000000000", FormatRate(rate))
+
+	rate, err = table.Rate(util.USD, util.USD)
+	require.NoError(t, err)
+	require.Equal(t, "1.0000000000", FormatRate(rate))
+
+	_, err = table.Rate(util.USD, util.RUB)
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestTableTriangulatedRate(t *testing.T) {
+	table := NewTable()
+	table.Add(util.EUR, util.USD, big.NewRat(5, 4))
+	table.Add(util.EUR, util.RUB, big.NewRat(100, 1))
+
+	rate, err := table.Rate(util.USD, util.RUB)
+	require.NoError(t, err)
+	require.Equal(t, "80.0000000000", FormatRate(rate))
+
+	rate, err = table.Rate(util.RUB, util.USD)
+	require.NoError(t, err)
+	require.Equal(t, "0.0125000000", FormatRate(rate))
+
+	_, err = table.Rate(util.USD, "GBP")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
@@ -0,0 +1,69 @@
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Precision is the number of decimal places accruals are kept with, in minor units.
+const Precision = 10
+
+// DaysPerYear is the day count convention, every day earns 1/365 of the yearly rate, leap years included.
+const DaysPerYear = 365
+
+// Daily is the interest earned in a day on an end of day balance at a yearly rate in basis points.
+// Negative balances earn nothing, overdrafts are charged separately.
+func Daily(balance int64, rateBps int64) *big.Rat {
+	if balance <= 0 || rateBps <= 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(
+		new(big.Int).Mul(big.NewInt(balance), big.NewInt(rateBps)),
+		big.NewInt(10000*DaysPerYear),
+	)
+}
+
+// Format formats an accrued amount with Precision decimal places, rounding half away from zero.
+func Format(amount *big.Rat) string {
+	return amount.FloatString(Precision)
+}
+
+// Parse parses an accrued amount.
+func Parse(s string) (*big.Rat, error) {
+	amount, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid interest amount %q", s)
+	}
+	return amount, nil
+}
+
+// Round rounds an accrued amount to whole minor units, halves go to the even unit
+// so rounding doesn't favor the bank or the customer over many payouts.
+func Round(amount *big.Rat) int64 {
+	quo, rem := new(big.Int).QuoRem(amount.Num(), amount.Denom(), new(big.Int))
+
+	// compare twice the remainder with the denominator to find which side of the half it is on
+	cmp := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(amount.Denom())
+	if cmp > 0 || (cmp == 0 && quo.Bit(0) == 1) {
+		if rem.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	return quo.Int64()
+}
+
+// Day truncates t to the start of its UTC day.
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MonthStart is the start of the UTC month of t, interest of the days before it is capitalized.
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
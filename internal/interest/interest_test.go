@@ -0,0 +1,56 @@
+package interest
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDaily(t *testing.T) {
+	require.Equal(t, "1.0000000000", Format(Daily(36500, 100)))
+	require.Equal(t, "0.0013698630", Format(Daily(1000, 5)))
+	require.Equal(t, "0.0000000000", Format(Daily(-1000, 500)))
+	require.Equal(t, "0.0000000000", Format(Daily(1000, 0)))
+}
+
+func TestRound(t *testing.T) {
+	testCases := []struct {
+		amount string
+		want   int64
+	}{
+		{"0", 0},
+		{"0.4999999999", 0},
+		{"0.5", 0},
+		{"1.5", 2},
+		{"2.5", 2},
+		{"2.5000000001", 3},
+		{"-1.5", -2},
+		{"-2.5", -2},
+		{"-2.6", -3},
+	}
+
+	for _, tc := range testCases {
+		amount, err := Parse(tc.amount)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, Round(amount), tc.amount)
+	}
+
+	_, err := Parse("abc")
+	require.Error(t, err)
+}
+
+func TestRoundMonth(t *testing.T) {
+	// 30 days of 1000.00 at 2.5% adds up to exactly 205.4794520548 minor units
+	total := new(big.Rat)
+	for i := 0; i < 30; i++ {
+		total.Add(total, Daily(100000, 250))
+	}
+	require.Equal(t, int64(205), Round(total))
+}
+
+func TestDay(t *testing.T) {
+	at := time.Date(2024, 2, 29, 23, 30, 0, 0, time.FixedZone("", -2*60*60))
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Day(at))
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), MonthStart(at))
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	db "gobank/internal/db/sqlc"
+	"time"
+)
+
+// ExpiredHolds releases the holds that were neither captured nor voided before they expired.
+type ExpiredHolds struct {
+	store db.Store
+}
+
+func NewExpiredHolds(store db.Store) *ExpiredHolds {
+	return &ExpiredHolds{
+		store: store,
+	}
+}
+
+func (j *ExpiredHolds) Name() string {
+	return "expired-holds"
+}
+
+// Run releases expired holds one transaction at a time until none is left.
+func (j *ExpiredHolds) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		_, err := j.store.ExpireHoldTx(ctx, time.Now())
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
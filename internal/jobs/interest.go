@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	db "gobank/internal/db/sqlc"
+	"log"
+	"time"
+)
+
+// Interest accrues the interest of every complete day, and pays the interest of every complete month.
+type Interest struct {
+	store db.Store
+}
+
+func NewInterest(store db.Store) *Interest {
+	return &Interest{
+		store: store,
+	}
+}
+
+func (j *Interest) Name() string {
+	return "interest"
+}
+
+// Run accrues interest one account day at a time, then capitalizes it one account at a time,
+// until everything is up to date. An account whose interest can't be paid is logged and skipped until the next run.
+func (j *Interest) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		_, err := j.store.AccrueInterestTx(ctx, time.Now())
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var skipped []int64
+	for ctx.Err() == nil {
+		_, err := j.store.CapitalizeInterestTx(ctx, db.CapitalizeInterestTxParams{
+			Now:        time.Now(),
+			SkippedIDs: skipped,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		var accountErr *db.AccountError
+		if errors.As(err, &accountErr) {
+			log.Printf("interest not capitalized, %v", accountErr)
+			skipped = append(skipped, accountErr.AccountID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/require"
+	db "gobank/internal/db/sqlc"
+	"testing"
+	"time"
+)
+
+// interestStore has nothing left to accrue and capitalizes the accounts in ID order,
+// the first one has no interest account.
+type interestStore struct {
+	db.Store
+	uncapitalized []int64
+	capitalized   []int64
+}
+
+func (s *interestStore) AccrueInterestTx(_ context.Context, _ time.Time) (db.InterestAccrual, error) {
+	return db.InterestAccrual{}, sql.ErrNoRows
+}
+
+func (s *interestStore) CapitalizeInterestTx(_ context.Context, arg db.CapitalizeInterestTxParams) (db.CapitalizeInterestTxResult, error) {
+	skipped := make(map[int64]bool)
+	for _, id := range arg.SkippedIDs {
+		skipped[id] = true
+	}
+
+	for i, id := range s.uncapitalized {
+		if skipped[id] {
+			continue
+		}
+		if i == 0 {
+			return db.CapitalizeInterestTxResult{}, &db.AccountError{AccountID: id, Err: errors.New("no EUR interest account")}
+		}
+
+		s.uncapitalized = append(s.uncapitalized[:i], s.uncapitalized[i+1:]...)
+		s.capitalized = append(s.capitalized, id)
+		return db.CapitalizeInterestTxResult{AccountID: id}, nil
+	}
+	return db.CapitalizeInterestTxResult{}, sql.ErrNoRows
+}
+
+func TestInterestSkipsFailedAccount(t *testing.T) {
+	store := &interestStore{uncapitalized: []int64{1, 2, 3}}
+
+	require.NoError(t, NewInterest(store).Run(context.Background()))
+	require.Equal(t, []int64{2, 3}, store.capitalized)
+	require.Equal(t, []int64{1}, store.uncapitalized)
+}
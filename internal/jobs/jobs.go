@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a background task that runs periodically next to the API server.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Every runs job right away and then every interval until ctx is done.
+// Errors are logged and the job runs again on the next tick.
+func Every(ctx context.Context, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("job %s failed: %v", job.Name(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
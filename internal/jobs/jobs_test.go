@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type countingJob struct {
+	runs chan struct{}
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	select {
+	case j.runs <- struct{}{}:
+	default:
+	}
+	return errors.New("failed jobs run again")
+}
+
+func TestEvery(t *testing.T) {
+	job := &countingJob{runs: make(chan struct{}, 10)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, time.Millisecond, job)
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-job.runs:
+		case <-time.After(time.Second):
+			t.Fatal("job didn't run")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Every didn't return after the context was cancelled")
+	}
+	require.Error(t, ctx.Err())
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"log"
+	"time"
+)
+
+// OverdraftFees charges overdrawn accounts a daily fee, posted to the system fees account of their currency.
+type OverdraftFees struct {
+	store  db.Store
+	config util.Config
+}
+
+func NewOverdraftFees(store db.Store, config util.Config) *OverdraftFees {
+	return &OverdraftFees{
+		store:  store,
+		config: config,
+	}
+}
+
+func (j *OverdraftFees) Name() string {
+	return "overdraft-fees"
+}
+
+// Run charges overdrawn accounts one transaction at a time until all of them were charged today.
+// An account that can't be charged is logged and skipped until the next run.
+func (j *OverdraftFees) Run(ctx context.Context) error {
+	var skipped []int64
+	for ctx.Err() == nil {
+		_, err := j.store.ChargeOverdraftFeeTx(ctx, db.ChargeOverdraftFeeTxParams{
+			Now:        time.Now(),
+			RateBps:    j.config.OverdraftFeeRateBps,
+			SkippedIDs: skipped,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		var accountErr *db.AccountError
+		if errors.As(err, &accountErr) {
+			log.Printf("overdraft fee not charged, %v", accountErr)
+			skipped = append(skipped, accountErr.AccountID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
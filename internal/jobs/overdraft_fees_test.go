@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/require"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"testing"
+)
+
+// overdraftStore charges the overdrawn accounts in ID order, the first one has no fees account.
+type overdraftStore struct {
+	db.Store
+	overdrawn []int64
+	charged   []int64
+}
+
+func (s *overdraftStore) ChargeOverdraftFeeTx(_ context.Context, arg db.ChargeOverdraftFeeTxParams) (db.TransferTxResult, error) {
+	skipped := make(map[int64]bool)
+	for _, id := range arg.SkippedIDs {
+		skipped[id] = true
+	}
+
+	for i, id := range s.overdrawn {
+		if skipped[id] {
+			continue
+		}
+		if i == 0 {
+			return db.TransferTxResult{}, &db.AccountError{AccountID: id, Err: errors.New("no EUR fees account")}
+		}
+
+		s.overdrawn = append(s.overdrawn[:i], s.overdrawn[i+1:]...)
+		s.charged = append(s.charged, id)
+		return db.TransferTxResult{Transfer: db.Transfer{SenderID: id}}, nil
+	}
+	return db.TransferTxResult{}, sql.ErrNoRows
+}
+
+func TestOverdraftFeesSkipsFailedAccount(t *testing.T) {
+	store := &overdraftStore{overdrawn: []int64{1, 2, 3}}
+	job := NewOverdraftFees(store, util.Config{OverdraftFeeRateBps: 100})
+
+	require.NoError(t, job.Run(context.Background()))
+	require.Equal(t, []int64{2, 3}, store.charged)
+	require.Equal(t, []int64{1}, store.overdrawn)
+}
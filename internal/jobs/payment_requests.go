@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	db "gobank/internal/db/sqlc"
+	"time"
+)
+
+// ExpiredPaymentRequests marks the payment requests that were neither accepted nor declined before they expired.
+type ExpiredPaymentRequests struct {
+	store db.Store
+}
+
+func NewExpiredPaymentRequests(store db.Store) *ExpiredPaymentRequests {
+	return &ExpiredPaymentRequests{
+		store: store,
+	}
+}
+
+func (j *ExpiredPaymentRequests) Name() string {
+	return "expired-payment-requests"
+}
+
+// Run expires every pending request at once, nothing else changes with them.
+func (j *ExpiredPaymentRequests) Run(ctx context.Context) error {
+	_, err := j.store.ExpirePaymentRequests(ctx, time.Now())
+	return err
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"log"
+	"time"
+)
+
+// ScheduledTransfers executes the scheduled transfers that are due.
+// Several instances can run at once, each scheduled transfer is claimed by one of them.
+type ScheduledTransfers struct {
+	store  db.Store
+	config util.Config
+}
+
+func NewScheduledTransfers(store db.Store, config util.Config) *ScheduledTransfers {
+	return &ScheduledTransfers{
+		store:  store,
+		config: config,
+	}
+}
+
+func (j *ScheduledTransfers) Name() string {
+	return "scheduled-transfers"
+}
+
+// Run executes scheduled transfers one transaction at a time until none is due.
+func (j *ScheduledTransfers) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		result, err := j.store.ExecuteScheduledTransferTx(ctx, db.ExecuteScheduledTransferTxParams{
+			Now:         time.Now(),
+			MaxAttempts: j.config.ScheduledTransferMaxAttempts,
+			RetryDelay:  j.config.ScheduledTransferRetryDelay,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if result.Run.Status == db.ScheduledTransferRunFailed {
+			log.Printf("scheduled transfer %d attempt %d failed: %s",
+				result.ScheduledTransfer.ID, result.Run.Attempt, result.Run.Error.String)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/fx"
+	"time"
+)
+
+type Kind string
+
+const (
+	BalanceDrift          Kind = "balance_drift"
+	AvailableBalanceDrift Kind = "available_balance_drift"
+	OrphanEntry           Kind = "orphan_entry"
+	UnbalancedTransfer    Kind = "unbalanced_transfer"
+	CurrencyMismatch      Kind = "currency_mismatch"
+	OverRefund            Kind = "over_refund"
+)
+
+type Violation struct {
+	Kind       Kind   `json:"kind"`
+	AccountID  int64  `json:"account_id,omitempty"`
+	TransferID int64  `json:"transfer_id,omitempty"`
+	EntryID    int64  `json:"entry_id,omitempty"`
+	Detail     string `json:"detail"`
+}
+
+type Report struct {
+	StartedAt       time.Time   `json:"started_at"`
+	FinishedAt      time.Time   `json:"finished_at"`
+	AccountsScanned int         `json:"accounts_scanned"`
+	Violations      []Violation `json:"violations"`
+}
+
+func (r Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// Verify scans all accounts in batches of batchSize and checks that:
+//   - the account balance equals the sum of its entries
+//   - the available balance equals the balance minus the active holds
+//   - every entry belongs to a transfer between its account and another one
+//   - every transfer has a debit entry of its amount and a credit entry of its recipient amount
+//   - transfers between accounts in the same currency don't convert, and transfers between currencies convert at their rate
+//   - refunds of a transfer don't add up to more than the transfer
+//
+// Each check is a single query, so it sees a consistent state even while transfers are being made.
+func Verify(ctx context.Context, q db.Querier, batchSize int32) (Report, error) {
+	report := Report{
+		StartedAt:  time.Now(),
+		Violations: []Violation{},
+	}
+
+	var afterID int64
+	for {
+		accounts, err := q.ListAccountLedgerBalances(ctx, db.ListAccountLedgerBalancesParams{
+			AfterID:  afterID,
+			PageSize: batchSize,
+		})
+		if err != nil {
+			return report, err
+		}
+		if len(accounts) == 0 {
+			break
+		}
+
+		lastID := accounts[len(accounts)-1].ID
+		report.AccountsScanned += len(accounts)
+
+		for _, account := range accounts {
+			if v, ok := checkBalance(account); !ok {
+				report.Violations = append(report.Violations, v)
+			}
+			if v, ok := checkAvailableBalance(account); !ok {
+				report.Violations = append(report.Violations, v)
+			}
+		}
+
+		if err := verifyBatch(ctx, q, afterID, lastID, &report); err != nil {
+			return report, err
+		}
+
+		if len(accounts) < int(batchSize) {
+			break
+		}
+		afterID = lastID
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// verifyBatch checks the entries of the accounts in (afterID, lastID] and the transfers sent by them.
+func verifyBatch(ctx context.Context, q db.Querier, afterID int64, lastID int64, report *Report) error {
+	entries, err := q.ListOrphanEntries(ctx, db.ListOrphanEntriesParams{
+		AfterAccountID: afterID,
+		LastAccountID:  lastID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		report.Violations = append(report.Violations, orphanEntry(entry))
+	}
+
+	transfers, err := q.ListUnbalancedTransfers(ctx, db.ListUnbalancedTransfersParams{
+		AfterAccountID: afterID,
+		LastAccountID:  lastID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfers {
+		report.Violations = append(report.Violations, unbalancedTransfer(transfer))
+	}
+
+	conversions, err := q.ListCurrencyConversions(ctx, db.ListCurrencyConversionsParams{
+		AfterAccountID: afterID,
+		LastAccountID:  lastID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, conversion := range conversions {
+		if v, ok := checkConversion(conversion); !ok {
+			report.Violations = append(report.Violations, v)
+		}
+	}
+
+	refunded, err := q.ListOverRefundedTransfers(ctx, db.ListOverRefundedTransfersParams{
+		AfterAccountID: afterID,
+		LastAccountID:  lastID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range refunded {
+		report.Violations = append(report.Violations, overRefund(transfer))
+	}
+
+	return nil
+}
+
+func checkBalance(account db.ListAccountLedgerBalancesRow) (Violation, bool) {
+	if account.Balance == account.EntriesBalance {
+		return Violation{}, true
+	}
+
+	return Violation{
+		Kind:      BalanceDrift,
+		AccountID: account.ID,
+		Detail:    fmt.Sprintf("balance is %d but entries sum to %d", account.Balance, account.EntriesBalance),
+	}, false
+}
+
+func checkAvailableBalance(account db.ListAccountLedgerBalancesRow) (Violation, bool) {
+	if account.AvailableBalance == account.Balance-account.HeldAmount {
+		return Violation{}, true
+	}
+
+	return Violation{
+		Kind:      AvailableBalanceDrift,
+		AccountID: account.ID,
+		Detail: fmt.Sprintf("available balance is %d but balance %d minus holds %d is %d",
+			account.AvailableBalance, account.Balance, account.HeldAmount, account.Balance-account.HeldAmount),
+	}, false
+}
+
+func orphanEntry(entry db.Entry) Violation {
+	v := Violation{
+		Kind:      OrphanEntry,
+		AccountID: entry.AccountID,
+		EntryID:   entry.ID,
+		Detail:    "entry doesn't belong to a transfer",
+	}
+
+	if entry.TransferID.Valid {
+		v.TransferID = entry.TransferID.Int64
+		v.Detail = "entry account is neither the sender nor the recipient of its transfer"
+	}
+
+	return v
+}
+
+func unbalancedTransfer(transfer db.ListUnbalancedTransfersRow) Violation {
+	return Violation{
+		Kind:       UnbalancedTransfer,
+		AccountID:  transfer.SenderID,
+		TransferID: transfer.ID,
+		Detail: fmt.Sprintf("expected 2 entries of %d and %d, got %d entries debiting %d and crediting %d",
+			-transfer.Amount, transfer.RecipientAmount, transfer.EntryCount,
+			transfer.SenderEntriesAmount, transfer.RecipientEntriesAmount),
+	}
+}
+
+func checkConversion(transfer db.ListCurrencyConversionsRow) (Violation, bool) {
+	v := Violation{
+		Kind:       CurrencyMismatch,
+		AccountID:  transfer.SenderID,
+		TransferID: transfer.ID,
+	}
+
+	if transfer.SenderCurrency == transfer.RecipientCurrency {
+		if transfer.ExchangeRate.Valid {
+			v.Detail = fmt.Sprintf("transfer within %s has exchange rate %s", transfer.SenderCurrency, transfer.ExchangeRate.String)
+			return v, false
+		}
+		if transfer.Amount != transfer.RecipientAmount {
+			v.Detail = fmt.Sprintf("transfer within %s debits %d but credits %d", transfer.SenderCurrency, transfer.Amount, transfer.RecipientAmount)
+			return v, false
+		}
+		return Violation{}, true
+	}
+
+	if !transfer.ExchangeRate.Valid {
+		v.Detail = fmt.Sprintf("transfer from %s to %s has no exchange rate", transfer.SenderCurrency, transfer.RecipientCurrency)
+		return v, false
+	}
+
+	// refunds are credited in proportion to the refunded transfer, they are checked by ListOverRefundedTransfers
+	if transfer.Kind == db.TransferKindRefund {
+		return Violation{}, true
+	}
+
+	rate, err := fx.ParseRate(transfer.ExchangeRate.String)
+	if err != nil {
+		v.Detail = err.Error()
+		return v, false
+	}
+
+	expected, err := fx.Convert(transfer.Amount, rate)
+	if err != nil {
+		v.Detail = err.Error()
+		return v, false
+	}
+
+	if expected != transfer.RecipientAmount {
+		v.Detail = fmt.Sprintf("%d %s at rate %s is %d %s, but %d were credited",
+			transfer.Amount, transfer.SenderCurrency, transfer.ExchangeRate.String,
+			expected, transfer.RecipientCurrency, transfer.RecipientAmount)
+		return v, false
+	}
+
+	return Violation{}, true
+}
+
+func overRefund(transfer db.ListOverRefundedTransfersRow) Violation {
+	return Violation{
+		Kind:       OverRefund,
+		AccountID:  transfer.SenderID,
+		TransferID: transfer.ID,
+		Detail: fmt.Sprintf("transfer of %d credited %d was refunded %d crediting back %d",
+			transfer.Amount, transfer.RecipientAmount, transfer.RefundedAmount, transfer.RefundedRecipientAmount),
+	}
+}
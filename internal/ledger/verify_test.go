@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	db "gobank/internal/db/sqlc"
+	"gobank/internal/util"
+	"testing"
+)
+
+func TestCheckBalance(t *testing.T) {
+	_, ok := checkBalance(db.ListAccountLedgerBalancesRow{ID: 1, Balance: 100, EntriesBalance: 100})
+	require.True(t, ok)
+
+	v, ok := checkBalance(db.ListAccountLedgerBalancesRow{ID: 1, Balance: 100, EntriesBalance: 90})
+	require.False(t, ok)
+	require.Equal(t, BalanceDrift, v.Kind)
+	require.Equal(t, int64(1), v.AccountID)
+}
+
+func TestCheckAvailableBalance(t *testing.T) {
+	_, ok := checkAvailableBalance(db.ListAccountLedgerBalancesRow{ID: 1, Balance: 100, AvailableBalance: 70, HeldAmount: 30})
+	require.True(t, ok)
+
+	v, ok := checkAvailableBalance(db.ListAccountLedgerBalancesRow{ID: 1, Balance: 100, AvailableBalance: 100, HeldAmount: 30})
+	require.False(t, ok)
+	require.Equal(t, AvailableBalanceDrift, v.Kind)
+}
+
+func TestOrphanEntry(t *testing.T) {
+	v := orphanEntry(db.Entry{ID: 3, AccountID: 1})
+	require.Equal(t, OrphanEntry, v.Kind)
+	require.Equal(t, int64(3), v.EntryID)
+	require.Zero(t, v.TransferID)
+
+	v = orphanEntry(db.Entry{ID: 3, AccountID: 1, TransferID: sql.NullInt64{Int64: 5, Valid: true}})
+	require.Equal(t, int64(5), v.TransferID)
+}
+
+func TestCheckConversion(t *testing.T) {
+	rate := sql.NullString{String: "1.0850000000", Valid: true}
+
+	testCases := []struct {
+		name     string
+		transfer db.ListCurrencyConversionsRow
+		ok       bool
+	}{
+		{
+			name: "SameCurrency",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 100, RecipientAmount: 100, SenderCurrency: util.EUR, RecipientCurrency: util.EUR,
+			},
+			ok: true,
+		},
+		{
+			name: "SameCurrencyDifferentAmounts",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 100, RecipientAmount: 99, SenderCurrency: util.EUR, RecipientCurrency: util.EUR,
+			},
+		},
+		{
+			name: "SameCurrencyWithRate",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 100, RecipientAmount: 100, ExchangeRate: rate, SenderCurrency: util.EUR, RecipientCurrency: util.EUR,
+			},
+		},
+		{
+			name: "Converted",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 1001, RecipientAmount: 1086, ExchangeRate: rate, SenderCurrency: util.EUR, RecipientCurrency: util.USD,
+			},
+			ok: true,
+		},
+		{
+			name: "WrongConversion",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 1001, RecipientAmount: 1087, ExchangeRate: rate, SenderCurrency: util.EUR, RecipientCurrency: util.USD,
+			},
+		},
+		{
+			name: "ProportionalRefund",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 543, RecipientAmount: 501, ExchangeRate: sql.NullString{String: "0.9216589862", Valid: true},
+				Kind: db.TransferKindRefund, SenderCurrency: util.USD, RecipientCurrency: util.EUR,
+			},
+			ok: true,
+		},
+		{
+			name: "MissingRate",
+			transfer: db.ListCurrencyConversionsRow{
+				Amount: 100, RecipientAmount: 100, SenderCurrency: util.EUR, RecipientCurrency: util.USD,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, ok := checkConversion(tc.transfer)
+			require.Equal(t, tc.ok, ok)
+			if !ok {
+				require.Equal(t, CurrencyMismatch, v.Kind)
+				require.NotEmpty(t, v.Detail)
+			}
+		})
+	}
+}
@@ -1,17 +1,49 @@
 package util
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
+	"gobank/internal/fees"
+	"path/filepath"
 	"time"
 )
 
 type Config struct {
 	DBDriver             string        `mapstructure:"DB_DRIVER"`
 	DBSource             string        `mapstructure:"DB_SOURCE"`
+	TxMaxRetries         int           `mapstructure:"TX_MAX_RETRIES"`
 	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	IdempotencyKeyTTL    time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	FxQuoteDuration      time.Duration `mapstructure:"FX_QUOTE_DURATION"`
+	HoldDuration         time.Duration `mapstructure:"HOLD_DURATION"`
+	FundingProvider      string        `mapstructure:"FUNDING_PROVIDER"`
+	BatchMaxLines        int           `mapstructure:"BATCH_MAX_LINES"`
+
+	// PaymentRequestDuration is how long a payment request can be accepted after it's made.
+	PaymentRequestDuration time.Duration `mapstructure:"PAYMENT_REQUEST_DURATION"`
+
+	// FrozenAccountsCanReceive lets frozen accounts receive transfers, they can never send.
+	FrozenAccountsCanReceive bool `mapstructure:"FROZEN_ACCOUNTS_CAN_RECEIVE"`
+
+	TransferLimitsEUR string `mapstructure:"TRANSFER_LIMITS_EUR"`
+	TransferLimitsUSD string `mapstructure:"TRANSFER_LIMITS_USD"`
+	TransferLimitsRUB string `mapstructure:"TRANSFER_LIMITS_RUB"`
+	// TransferLimits holds the parsed default limits by currency.
+	TransferLimits map[string]Limits `mapstructure:"-"`
+
+	// FeeRulesFile is a JSON file of fee rules, relative to the config path. No fees are charged without it.
+	FeeRulesFile string      `mapstructure:"FEE_RULES_FILE"`
+	FeeRules     []fees.Rule `mapstructure:"-"`
+
+	SchedulerInterval            time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	ScheduledTransferMaxAttempts int32         `mapstructure:"SCHEDULED_TRANSFER_MAX_ATTEMPTS"`
+	ScheduledTransferRetryDelay  time.Duration `mapstructure:"SCHEDULED_TRANSFER_RETRY_DELAY"`
+
+	// OverdraftFeeRateBps is the daily overdraft fee in basis points of the overdrawn balance, 0 disables it.
+	OverdraftFeeRateBps int64 `mapstructure:"OVERDRAFT_FEE_RATE_BPS"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -27,5 +59,38 @@ func LoadConfig(path string) (config Config, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	// the scheduler ticker panics on a zero interval and no batch fits in zero lines
+	if config.SchedulerInterval <= 0 {
+		err = fmt.Errorf("SCHEDULER_INTERVAL must be positive, got %s", config.SchedulerInterval)
+		return
+	}
+	if config.BatchMaxLines <= 0 {
+		err = fmt.Errorf("BATCH_MAX_LINES must be positive, got %d", config.BatchMaxLines)
+		return
+	}
+
+	config.TransferLimits = make(map[string]Limits)
+	for currency, raw := range map[string]string{
+		EUR: config.TransferLimitsEUR,
+		USD: config.TransferLimitsUSD,
+		RUB: config.TransferLimitsRUB,
+	} {
+		config.TransferLimits[currency], err = ParseLimits(raw)
+		if err != nil {
+			err = fmt.Errorf("default %s transfer limits: %w", currency, err)
+			return
+		}
+	}
+
+	rulesFile := config.FeeRulesFile
+	if rulesFile != "" && !filepath.IsAbs(rulesFile) {
+		rulesFile = filepath.Join(path, rulesFile)
+	}
+
+	config.FeeRules, err = fees.LoadFile(rulesFile)
 	return
 }
@@ -8,6 +8,10 @@ const (
 	RUB = "RUB"
 )
 
+func SupportedCurrencies() []string {
+	return []string{EUR, USD, RUB}
+}
+
 func IsSupportedCurrency(currency string) bool {
 	switch currency {
 	case USD, EUR, RUB:
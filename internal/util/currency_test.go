@@ -19,4 +19,10 @@ func TestIsSupportedCurrency(t *testing.T) {
 	require.Equal(t, true, res)
 }
 
+func TestSupportedCurrencies(t *testing.T) {
+	for _, currency := range SupportedCurrencies() {
+		require.True(t, IsSupportedCurrency(currency))
+	}
+}
+
 // TODO: test register validator
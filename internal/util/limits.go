@@ -0,0 +1,52 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits caps the transfers sent from an account, amounts are in minor units of the account currency.
+// A zero limit means no limit.
+type Limits struct {
+	PerTransfer   int64 `json:"per_transfer"`
+	DailyAmount   int64 `json:"daily_amount"`
+	MonthlyAmount int64 `json:"monthly_amount"`
+	DailyCount    int64 `json:"daily_count"`
+	MonthlyCount  int64 `json:"monthly_count"`
+}
+
+// ParseLimits parses space separated name=value pairs, e.g. "per_transfer=100000 daily_count=50".
+// Limits that are left out are not limited.
+func ParseLimits(s string) (Limits, error) {
+	var limits Limits
+
+	for _, field := range strings.Fields(s) {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return limits, fmt.Errorf("invalid limit %q, expected name=value", field)
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return limits, fmt.Errorf("invalid %s limit %q", name, value)
+		}
+
+		switch name {
+		case "per_transfer":
+			limits.PerTransfer = n
+		case "daily_amount":
+			limits.DailyAmount = n
+		case "monthly_amount":
+			limits.MonthlyAmount = n
+		case "daily_count":
+			limits.DailyCount = n
+		case "monthly_count":
+			limits.MonthlyCount = n
+		default:
+			return limits, fmt.Errorf("unknown limit %q", name)
+		}
+	}
+
+	return limits, nil
+}
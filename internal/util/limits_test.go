@@ -0,0 +1,27 @@
+package util
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("per_transfer=100  daily_amount=500 monthly_amount=2000 daily_count=5 monthly_count=50")
+	require.NoError(t, err)
+	require.Equal(t, Limits{
+		PerTransfer:   100,
+		DailyAmount:   500,
+		MonthlyAmount: 2000,
+		DailyCount:    5,
+		MonthlyCount:  50,
+	}, limits)
+
+	limits, err = ParseLimits("")
+	require.NoError(t, err)
+	require.Zero(t, limits)
+
+	for _, invalid := range []string{"per_transfer", "per_transfer=-1", "per_transfer=ten", "weekly_amount=10"} {
+		_, err = ParseLimits(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -45,7 +45,7 @@ func RandomMoney() int64 {
 }
 
 func RandomCurrency() string {
-	currencies := []string{EUR, USD, RUB}
+	currencies := SupportedCurrencies()
 	n := len(currencies)
 	return currencies[rnd.Intn(n)]
 }
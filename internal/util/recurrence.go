@@ -0,0 +1,77 @@
+package util
+
+import (
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+const (
+	Once    = "once"
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+func IsSupportedRecurrence(recurrence string) bool {
+	switch recurrence {
+	case Once, Daily, Weekly, Monthly, Yearly:
+		return true
+	}
+	return false
+}
+
+var RecurrenceValidator validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if recurrence, ok := fieldLevel.Field().Interface().(string); ok {
+		return IsSupportedRecurrence(recurrence)
+	}
+	return false
+}
+
+// NextOccurrence returns the first occurrence of the recurrence counted from start that is after the given time.
+// Monthly and yearly occurrences keep the day of start, clamped to the end of shorter months.
+// The second result is false when there is no such occurrence, i.e. a one-off that is already past.
+func NextOccurrence(recurrence string, start time.Time, after time.Time) (time.Time, bool) {
+	if start.After(after) {
+		return start, true
+	}
+
+	switch recurrence {
+	case Daily, Weekly:
+		days := 1
+		if recurrence == Weekly {
+			days = 7
+		}
+		// stepping in calendar days keeps the time of day across DST changes
+		n := int(after.Sub(start).Hours()/24)/days + 1
+		next := start.AddDate(0, 0, n*days)
+		for !next.After(after) {
+			n++
+			next = start.AddDate(0, 0, n*days)
+		}
+		return next, true
+	case Monthly, Yearly:
+		months := 1
+		if recurrence == Yearly {
+			months = 12
+		}
+		n := 1
+		next := addMonths(start, months)
+		for !next.After(after) {
+			n++
+			next = addMonths(start, n*months)
+		}
+		return next, true
+	}
+
+	return time.Time{}, false
+}
+
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
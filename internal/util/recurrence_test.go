@@ -0,0 +1,46 @@
+package util
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestIsSupportedRecurrence(t *testing.T) {
+	for _, recurrence := range []string{Once, Daily, Weekly, Monthly, Yearly} {
+		require.True(t, IsSupportedRecurrence(recurrence))
+	}
+	require.False(t, IsSupportedRecurrence("hourly"))
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		recurrence string
+		after      time.Time
+		next       time.Time
+		ok         bool
+	}{
+		{Once, start.Add(-time.Hour), start, true},
+		{Once, start, time.Time{}, false},
+		{Daily, start, time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC), true},
+		{Daily, time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC), time.Date(2023, 3, 11, 9, 0, 0, 0, time.UTC), true},
+		{Weekly, start, time.Date(2023, 2, 7, 9, 0, 0, 0, time.UTC), true},
+		{Monthly, start, time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), true},
+		{Monthly, time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), time.Date(2023, 3, 31, 9, 0, 0, 0, time.UTC), true},
+		{Monthly, time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 4, 30, 9, 0, 0, 0, time.UTC), true},
+		{Yearly, start, time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tc := range testCases {
+		next, ok := NextOccurrence(tc.recurrence, start, tc.after)
+		require.Equal(t, tc.ok, ok, tc.recurrence)
+		require.Equal(t, tc.next, next, tc.recurrence)
+	}
+
+	leapDay := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	next, ok := NextOccurrence(Yearly, leapDay, leapDay)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), next)
+}
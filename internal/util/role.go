@@ -0,0 +1,8 @@
+package util
+
+const (
+	CustomerRole = "customer"
+	AdminRole    = "admin"
+	// SystemRole owns the system accounts, it can't sign in.
+	SystemRole = "system"
+)